@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ai-gateway-telegram/pkg/auth"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// authCallbackPrefix namespaces the inline Approve/Reject buttons sent
+// to admins when a new user requests access.
+const authCallbackPrefix = "auth:"
+
+// handleUnauthorized is the router.Authorize deny handler. It runs on
+// every update from a non-authorized user, so it must not re-notify
+// admins on each retry: it only pings them the first time a user's
+// access request transitions into StatusPending, and never re-pings for
+// a user an admin has already denied.
+func handleUnauthorized(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	user := updateUserFrom(update)
+	chatID := updateChatID(update)
+	if user == nil || chatID == 0 {
+		return nil
+	}
+
+	locale := localeFor(user.ID, user.LanguageCode)
+
+	status, err := authz.Status(user.ID)
+	if err != nil {
+		return err
+	}
+	if status == auth.StatusDenied {
+		_, err := bot.Send(tgbotapi.NewMessage(chatID, translations.T(locale, "auth.access_denied")))
+		return err
+	}
+
+	created, err := authz.RequestAccess(user.ID)
+	if err != nil {
+		return err
+	}
+	if !created {
+		_, err := bot.Send(tgbotapi.NewMessage(chatID, translations.T(locale, "auth.request_pending")))
+		return err
+	}
+
+	_, err = bot.Send(tgbotapi.NewMessage(chatID, translations.T(locale, "auth.request_sent")))
+	if err != nil {
+		return err
+	}
+
+	for _, adminID := range authz.Admins() {
+		adminLocale := localeFor(adminID, "")
+		markup := tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(translations.T(adminLocale, "auth.approve"), fmt.Sprintf("%sapprove:%d", authCallbackPrefix, user.ID)),
+				tgbotapi.NewInlineKeyboardButtonData(translations.T(adminLocale, "auth.reject"), fmt.Sprintf("%sreject:%d", authCallbackPrefix, user.ID)),
+			),
+		)
+		msg := tgbotapi.NewMessage(adminID, translations.T(adminLocale, "auth.requested", user.UserName, user.ID))
+		msg.ReplyMarkup = markup
+		if _, err := bot.Send(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleAuthCallback processes an admin tapping Approve/Reject on a
+// pending-access notification.
+func handleAuthCallback(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	query := update.CallbackQuery
+	locale := localeFor(query.From.ID, query.From.LanguageCode)
+	if !authz.IsAdmin(query.From.ID) {
+		_, err := bot.Send(tgbotapi.NewCallback(query.ID, translations.T(locale, "auth.admins_only")))
+		return err
+	}
+
+	action, idStr, ok := strings.Cut(strings.TrimPrefix(query.Data, authCallbackPrefix), ":")
+	if !ok {
+		return nil
+	}
+	userID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	var resultKey string
+	switch action {
+	case "approve":
+		err = authz.Allow(userID)
+		resultKey = "auth.allow_result"
+	case "reject":
+		err = authz.Deny(userID)
+		resultKey = "auth.deny_result"
+	}
+	if err != nil {
+		return err
+	}
+
+	result := translations.T(locale, resultKey, userID)
+	bot.Send(tgbotapi.NewCallback(query.ID, result))
+	msg := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID,
+		query.Message.Text+"\n\n-> "+result)
+	_, err = bot.Send(msg)
+	return err
+}
+
+// handleAllowCommand lets an admin grant access: "/allow <user_id>".
+func handleAllowCommand(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	return adminSetStatus(bot, update, authz.Allow, "auth.allow_result")
+}
+
+// handleDenyCommand lets an admin revoke/refuse access: "/deny <user_id>".
+func handleDenyCommand(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	return adminSetStatus(bot, update, authz.Deny, "auth.deny_result")
+}
+
+func adminSetStatus(bot *tgbotapi.BotAPI, update tgbotapi.Update, set func(int64) error, resultKey string) error {
+	message := update.Message
+	locale := localeFor(message.From.ID, message.From.LanguageCode)
+	if !authz.IsAdmin(message.From.ID) {
+		_, err := bot.Send(tgbotapi.NewMessage(message.Chat.ID, translations.T(locale, "auth.admins_only")))
+		return err
+	}
+
+	_, arg, _ := strings.Cut(message.Text, " ")
+	userID, err := strconv.ParseInt(strings.TrimSpace(arg), 10, 64)
+	if err != nil {
+		_, sendErr := bot.Send(tgbotapi.NewMessage(message.Chat.ID, translations.T(locale, "auth.usage_allow")))
+		return sendErr
+	}
+
+	if err := set(userID); err != nil {
+		return err
+	}
+	_, err = bot.Send(tgbotapi.NewMessage(message.Chat.ID, translations.T(locale, resultKey, userID)))
+	return err
+}
+
+// handlePendingCommand lists users awaiting an admin decision.
+func handlePendingCommand(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	message := update.Message
+	locale := localeFor(message.From.ID, message.From.LanguageCode)
+	if !authz.IsAdmin(message.From.ID) {
+		_, err := bot.Send(tgbotapi.NewMessage(message.Chat.ID, translations.T(locale, "auth.admins_only")))
+		return err
+	}
+
+	ids, err := authz.Pending()
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		_, err := bot.Send(tgbotapi.NewMessage(message.Chat.ID, translations.T(locale, "auth.none_pending")))
+		return err
+	}
+
+	lines := make([]string, len(ids))
+	for i, id := range ids {
+		lines[i] = strconv.FormatInt(id, 10)
+	}
+	_, err = bot.Send(tgbotapi.NewMessage(message.Chat.ID, translations.T(locale, "auth.pending_list", strings.Join(lines, ", "))))
+	return err
+}
+
+func updateUserFrom(update tgbotapi.Update) *tgbotapi.User {
+	switch {
+	case update.Message != nil:
+		return update.Message.From
+	case update.CallbackQuery != nil:
+		return update.CallbackQuery.From
+	default:
+		return nil
+	}
+}
+
+func updateChatID(update tgbotapi.Update) int64 {
+	switch {
+	case update.Message != nil && update.Message.Chat != nil:
+		return update.Message.Chat.ID
+	case update.CallbackQuery != nil && update.CallbackQuery.Message != nil:
+		return update.CallbackQuery.Message.Chat.ID
+	default:
+		return 0
+	}
+}
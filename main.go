@@ -2,56 +2,115 @@ package main
 
 import (
 	"ai-gateway-telegram/pkg"
-	"bufio"
+	"ai-gateway-telegram/pkg/auth"
+	"ai-gateway-telegram/pkg/dispatcher"
+	"ai-gateway-telegram/pkg/i18n"
+	"ai-gateway-telegram/pkg/providers"
+	"ai-gateway-telegram/pkg/router"
+	"ai-gateway-telegram/pkg/session"
+	"ai-gateway-telegram/pkg/transport"
 	"context"
-	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-var (
-	// Menu texts
-	firstMenu  = "<b>Menu 1</b>\n\nA beautiful menu with a shiny inline button."
-	secondMenu = "<b>Menu 2</b>\n\nA better menu with even more shiny inline buttons."
+// serveMetrics starts a small HTTP server exposing Prometheus metrics
+// on METRICS_LISTEN (default ":9090"), if that port can be bound.
+func serveMetrics() {
+	listen := os.Getenv("METRICS_LISTEN")
+	if listen == "" {
+		listen = ":9090"
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
 
-	// Button texts
-	nextButton     = "Next"
-	backButton     = "Back"
-	tutorialButton = "Tutorial"
+	go func() {
+		if err := http.ListenAndServe(listen, mux); err != nil {
+			log.Printf("An error occured: %s", err.Error())
+		}
+	}()
+}
 
-	// Store bot screaming status
-	screaming = false
+// Number of streamed chunks to accumulate before pushing an
+// EditMessageText update, to stay well under Telegram's rate limits.
+const streamEditEvery = 20
+
+var (
+	// Button callback data. These are internal identifiers, not
+	// user-facing copy, so they stay fixed regardless of locale.
+	nextButton = "Next"
+	backButton = "Back"
+
+	// Store bot screaming status. Set/read from different chats' lanes
+	// concurrently, hence atomic.Bool rather than a plain bool.
+	screaming atomic.Bool
 	bot       *tgbotapi.BotAPI
 
-	// Keyboard layout for the first menu. One button, one row
-	firstMenuMarkup = tgbotapi.NewInlineKeyboardMarkup(
+	// Per-user provider/conversation state
+	sessions *session.SessionManager
+
+	// Registry of configured LLM providers, keyed by name (pkg.ChatGPT etc)
+	registry *providers.Registry
+
+	// setup walks a user through "pick provider -> enter system prompt"
+	// before they start chatting.
+	setup *router.ConversationHandler
+
+	// authz gates every handler behind ALLOWED_USER_IDS/ADMIN_USER_IDS
+	// plus runtime /allow and /deny decisions.
+	authz *auth.Authorizer
+
+	// translations loaded from i18n/, selected per-user by localeFor.
+	translations *i18n.Catalog
+
+	startMenuMarkup = tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(nextButton, nextButton),
+			tgbotapi.NewInlineKeyboardButtonData(pkg.ChatGPT, pkg.ChatGPT),
+			tgbotapi.NewInlineKeyboardButtonData(pkg.Claude, pkg.Claude),
+			tgbotapi.NewInlineKeyboardButtonData(pkg.Gemini, pkg.Gemini),
 		),
 	)
+)
 
-	// Keyboard layout for the second menu. Two buttons, one per row
-	secondMenuMarkup = tgbotapi.NewInlineKeyboardMarkup(
+// firstMenuMarkup is the keyboard layout for the first menu: one
+// button, one row, with a label translated for locale.
+func firstMenuMarkup(locale string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(backButton, backButton),
-		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonURL(tutorialButton, "https://core.telegram.org/bots/api"),
+			tgbotapi.NewInlineKeyboardButtonData(translations.T(locale, "button.next"), nextButton),
 		),
 	)
+}
 
-	startMenuMarkup = tgbotapi.NewInlineKeyboardMarkup(
+// secondMenuMarkup is the keyboard layout for the second menu: two
+// buttons, one per row, with labels translated for locale.
+func secondMenuMarkup(locale string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(pkg.ChatGPT, pkg.ChatGPT),
-			tgbotapi.NewInlineKeyboardButtonData(pkg.Claude, pkg.Claude),
-			tgbotapi.NewInlineKeyboardButtonData(pkg.Gemini, pkg.Gemini),
+			tgbotapi.NewInlineKeyboardButtonData(translations.T(locale, "button.back"), backButton),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL(translations.T(locale, "button.tutorial"), "https://core.telegram.org/bots/api"),
 		),
 	)
-)
+}
+
+// localeFor resolves which translations bundle to use for userID.
+func localeFor(userID int64, languageCode string) string {
+	return translations.LocaleFor(userID, languageCode)
+}
 
 func main() {
 	if err := godotenv.Load(); err != nil {
@@ -68,146 +127,491 @@ func main() {
 	// Set this to true to log all interactions with telegram servers
 	bot.Debug = false
 
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
+	translations, err = i18n.Load(envOr("I18N_DIR", "i18n"), envOr("DEFAULT_LOCALE", "en"))
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	// Create a new cancellable background context. Calling `cancel()` leads to the cancellation of the context
-	ctx := context.Background()
-	ctx, cancel := context.WithCancel(ctx)
+	sessions = session.NewSessionManager()
+	registry = providers.NewRegistry(
+		providers.NewOpenAI(os.Getenv("OPENAI_API_KEY")),
+		providers.NewAnthropic(os.Getenv("ANTHROPIC_API_KEY")),
+		providers.NewGoogle(os.Getenv("GOOGLE_API_KEY")),
+	)
+	setup = newSetupConversation()
 
-	// `updates` is a golang channel which receives telegram updates
-	updates := bot.GetUpdatesChan(u)
+	authStore, err := auth.NewBoltStore(envOr("AUTH_DB_PATH", "auth.db"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	authz = auth.NewAuthorizer(parseIDs(os.Getenv("ALLOWED_USER_IDS")), parseIDs(os.Getenv("ADMIN_USER_IDS")), authStore)
 
-	// Pass cancellable context to goroutine
-	go receiveUpdates(ctx, updates)
+	mux := newMux()
 
-	// Tell the user the bot is online
-	log.Println("Start listening for updates. Press enter to stop")
+	// Cancel on SIGINT/SIGTERM instead of waiting on stdin, so the bot
+	// shuts down cleanly under systemd/containers too.
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
 
-	// Wait for a newline symbol, then cancel handling updates
-	bufio.NewReader(os.Stdin).ReadBytes('\n')
-	cancel()
+	tp, err := transport.FromEnv(bot)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	updates, err := tp.Start(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
 
+	if minutes := transport.IdleTimeoutFromEnv(); minutes > 0 {
+		activity := make(chan struct{}, 1)
+		transport.WatchIdle(ctx, cancel, activity, time.Duration(minutes)*time.Minute)
+		updates = tapActivity(updates, activity)
+	}
+
+	disp := dispatcher.New(workersFromEnv(),
+		func(ctx context.Context, update tgbotapi.Update) error {
+			err := mux.Dispatch(ctx, bot, update)
+			if _, ok := err.(router.ErrNoRoute); ok {
+				return nil
+			}
+			return err
+		},
+		dispatcher.WithErrorHandler(func(chatID int64, err error) {
+			log.Printf("An error occured: %s", err.Error())
+			if chatID != 0 {
+				bot.Send(tgbotapi.NewMessage(chatID, translations.T(translations.LocaleFor(chatID, ""), "error.generic")))
+			}
+		}),
+	)
+	serveMetrics()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		receiveUpdates(ctx, disp, updates)
+	}()
+
+	log.Println("Start listening for updates. Press Ctrl+C to stop")
+	<-ctx.Done()
+
+	// Wait for receiveUpdates to stop handing updates to the dispatcher
+	// before shutting the transport down. It returns as soon as ctx is
+	// done: updates still queued in the dispatcher's lanes are dropped,
+	// and handlers already running are not waited for.
+	<-done
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+	if err := tp.Stop(shutdownCtx); err != nil {
+		log.Printf("An error occured: %s", err.Error())
+	}
+}
+
+// tapActivity forwards every update from in to the returned channel,
+// also signalling activity (non-blockingly) so an idle-timeout watcher
+// knows updates are still flowing.
+func tapActivity(in tgbotapi.UpdatesChannel, activity chan<- struct{}) tgbotapi.UpdatesChannel {
+	out := make(chan tgbotapi.Update)
+	go func() {
+		defer close(out)
+		for update := range in {
+			select {
+			case activity <- struct{}{}:
+			default:
+			}
+			out <- update
+		}
+	}()
+	return out
 }
 
-func receiveUpdates(ctx context.Context, updates tgbotapi.UpdatesChannel) {
-	// `for {` means the loop is infinite until we manually stop it
+// newMux builds the route table, replacing the old if/else ladder in
+// handleUpdate/handleMessage/handleCommand/handleButton.
+func newMux() *router.Mux {
+	mux := router.New()
+	mux.Use(router.Recover(log.Default()))
+	mux.Use(router.Logging(log.Default()))
+	mux.Use(router.Authorize(authz.IsAuthorized, handleUnauthorized))
+
+	mux.Handle(router.IsCommand("start"), handleStartCommand)
+	mux.Handle(router.IsCommand("menu"), handleMenuCommand)
+	mux.Handle(router.IsCommand("scream"), handleScreamCommand)
+	mux.Handle(router.IsCommand("whisper"), handleWhisperCommand)
+	mux.Handle(router.IsCommand("reset"), handleResetCommand)
+	mux.Handle(router.IsCommand("model"), handleModelCommandRoute)
+	mux.Handle(router.IsCommand("setup"), setup.Start)
+	mux.Handle(router.IsCommand("allow"), handleAllowCommand)
+	mux.Handle(router.IsCommand("deny"), handleDenyCommand)
+	mux.Handle(router.IsCommand("pending"), handlePendingCommand)
+	mux.Handle(router.IsCommand("lang"), handleLangCommand)
+
+	mux.Handle(router.IsCallbackData(authCallbackPrefix), handleAuthCallback)
+
+	// setup.Filter() must come before the plain provider-button routes:
+	// /setup shows the same startMenuMarkup buttons, and a user with an
+	// active conversation tapping one must advance pickProvider.Handle,
+	// not fall into handleProviderButton and get stuck forever.
+	mux.Handle(setup.Filter(), setup.Handler())
+
+	mux.Handle(router.IsCallbackData(pkg.ChatGPT), handleProviderButton)
+	mux.Handle(router.IsCallbackData(pkg.Claude), handleProviderButton)
+	mux.Handle(router.IsCallbackData(pkg.Gemini), handleProviderButton)
+	mux.Handle(router.IsCallbackData(nextButton), handleMenuButton)
+	mux.Handle(router.IsCallbackData(backButton), handleMenuButton)
+
+	// Catch-all: any remaining message, text or not.
+	mux.Handle(func(update tgbotapi.Update) bool { return update.Message != nil }, handleFreeformMessage)
+
+	return mux
+}
+
+// receiveUpdates hands each update to the dispatcher's worker pool
+// instead of processing it inline, so a slow handler in one chat can't
+// block updates for every other chat.
+func receiveUpdates(ctx context.Context, disp *dispatcher.Dispatcher, updates tgbotapi.UpdatesChannel) {
+	disp.Run(ctx)
+
 	for {
 		select {
-		// stop looping if ctx is cancelled
 		case <-ctx.Done():
 			return
-		// receive update from channel and then handle it
 		case update := <-updates:
-			handleUpdate(update)
+			disp.Dispatch(ctx, update)
 		}
 	}
 }
 
-func handleUpdate(update tgbotapi.Update) {
-	switch {
-	// Handle messages
-	case update.Message != nil:
-		handleMessage(update.Message)
-		break
+// workersFromEnv reads WORKERS, falling back to runtime.NumCPU() (via
+// dispatcher.New) when unset or invalid.
+func workersFromEnv() int {
+	n, err := strconv.Atoi(os.Getenv("WORKERS"))
+	if err != nil {
+		return 0
+	}
+	return n
+}
 
-	// Handle button clicks
-	case update.CallbackQuery != nil:
-		handleButton(update.CallbackQuery)
-		break
+// envOr returns the named env var, or fallback if it's unset/empty.
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
 	}
+	return fallback
 }
 
-func handleMessage(message *tgbotapi.Message) {
-	user := message.From
-	text := message.Text
+// parseIDs parses a comma-separated list of Telegram user IDs, e.g. the
+// value of ALLOWED_USER_IDS or ADMIN_USER_IDS. Invalid entries are skipped.
+func parseIDs(csv string) []int64 {
+	var ids []int64
+	for _, field := range strings.Split(csv, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
 
+func logMessage(message *tgbotapi.Message) {
+	user := message.From
 	log.Printf("id: %d", user.ID)
 	log.Printf("username: %s", user.UserName)
 	log.Printf("lang code: %s", user.LanguageCode)
 	log.Printf("name: %s %s", user.FirstName, user.LastName)
+	log.Printf("%s wrote %s", user.FirstName, message.Text)
+}
+
+func handleStartCommand(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	logMessage(update.Message)
+	locale := localeFor(update.Message.From.ID, update.Message.From.LanguageCode)
+	return hello(update.Message.Chat.ID, locale, update.Message.From.FirstName)
+}
+
+func handleMenuCommand(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	logMessage(update.Message)
+	locale := localeFor(update.Message.From.ID, update.Message.From.LanguageCode)
+	return sendMenu(update.Message.Chat.ID, locale)
+}
 
+func handleScreamCommand(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	screaming.Store(true)
+	return nil
+}
+
+func handleWhisperCommand(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	screaming.Store(false)
+	return nil
+}
+
+func handleResetCommand(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	sessions.Reset(update.Message.From.ID)
+	locale := localeFor(update.Message.From.ID, update.Message.From.LanguageCode)
+	_, err := bot.Send(tgbotapi.NewMessage(update.Message.Chat.ID, translations.T(locale, "reset.cleared")))
+	return err
+}
+
+// handleLangCommand lets a user override their locale: "/lang <code>".
+func handleLangCommand(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	message := update.Message
+	locale := localeFor(message.From.ID, message.From.LanguageCode)
+
+	_, arg, _ := strings.Cut(message.Text, " ")
+	code := strings.TrimSpace(arg)
+	if code == "" {
+		_, err := bot.Send(tgbotapi.NewMessage(message.Chat.ID, translations.T(locale, "lang.usage")))
+		return err
+	}
+	if !translations.Has(code) {
+		_, err := bot.Send(tgbotapi.NewMessage(message.Chat.ID, translations.T(locale, "lang.unknown", code)))
+		return err
+	}
+
+	translations.SetUserLocale(message.From.ID, code)
+	_, err := bot.Send(tgbotapi.NewMessage(message.Chat.ID, translations.T(code, "lang.set", code)))
+	return err
+}
+
+func handleModelCommandRoute(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	_, arg, _ := strings.Cut(update.Message.Text, " ")
+	locale := localeFor(update.Message.From.ID, update.Message.From.LanguageCode)
+	return handleModelCommand(update.Message.Chat.ID, update.Message.From.ID, locale, strings.TrimSpace(arg))
+}
+
+// handleModelCommand switches the model used within the user's
+// currently selected provider. With no argument it lists the models
+// available for that provider.
+func handleModelCommand(chatId, userId int64, locale, model string) error {
+	sess := sessions.Get(userId)
+	if sess.Provider() == "" {
+		_, err := bot.Send(tgbotapi.NewMessage(chatId, translations.T(locale, "model.pick_provider_first")))
+		return err
+	}
+
+	provider, err := registry.Get(sess.Provider())
+	if err != nil {
+		return err
+	}
+
+	if model == "" {
+		msg := translations.T(locale, "model.available", strings.Join(provider.Models(), ", "))
+		_, err := bot.Send(tgbotapi.NewMessage(chatId, msg))
+		return err
+	}
+
+	sessions.SetModel(userId, model)
+	_, err = bot.Send(tgbotapi.NewMessage(chatId, translations.T(locale, "model.set", model)))
+	return err
+}
+
+func handleFreeformMessage(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	message := update.Message
+	user := message.From
 	if user == nil {
-		return
+		return nil
 	}
+	logMessage(message)
 
-	// Print to console
-	log.Printf("%s wrote %s", user.FirstName, text)
+	text := message.Text
+	sess := sessions.Get(user.ID)
 
-	var err error
-	if strings.HasPrefix(text, "/") {
-		err = handleCommand(message.Chat.ID, text, user.FirstName)
-	} else if screaming && len(text) > 0 {
+	switch {
+	case sess.Provider() != "" && len(text) > 0:
+		return handleProviderMessage(ctx, message.Chat.ID, user.ID, text)
+	case screaming.Load() && len(text) > 0:
 		msg := tgbotapi.NewMessage(message.Chat.ID, strings.ToUpper(text))
 		// To preserve markdown, we attach entities (bold, italic..)
 		msg.Entities = message.Entities
-		_, err = bot.Send(msg)
-	} else {
+		_, err := bot.Send(msg)
+		return err
+	default:
 		// This is equivalent to forwarding, without the sender's name
 		copyMsg := tgbotapi.NewCopyMessage(message.Chat.ID, message.Chat.ID, message.MessageID)
-		_, err = bot.CopyMessage(copyMsg)
+		_, err := bot.CopyMessage(copyMsg)
+		return err
 	}
+}
+
+// handleProviderMessage sends text to the user's selected provider and
+// streams the reply back, editing a single placeholder message in place
+// every streamEditEvery chunks to stay under Telegram's rate limits.
+func handleProviderMessage(ctx context.Context, chatID, userID int64, text string) error {
+	sess := sessions.Get(userID)
 
+	provider, err := registry.Get(sess.Provider())
 	if err != nil {
-		log.Printf("An error occured: %s", err.Error())
+		return err
 	}
-}
 
-// When we get a command, we react accordingly
-func handleCommand(chatId int64, command, firstName string) error {
-	var err error
+	sessions.Append(userID, providers.Message{Role: providers.RoleUser, Content: text})
 
-	switch command {
-	case "/start":
-		err = hello(chatId, firstName)
+	placeholder, err := bot.Send(tgbotapi.NewMessage(chatID, "…"))
+	if err != nil {
+		return err
+	}
 
-	case "/scream":
-		screaming = true
+	chunks, err := provider.Complete(ctx, sess.Model(), sess.Messages())
+	if err != nil {
+		return err
+	}
 
-	case "/whisper":
-		screaming = false
+	var reply strings.Builder
+	sinceEdit := 0
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			return chunk.Err
+		}
+
+		reply.WriteString(chunk.Content)
+		sinceEdit++
 
-	case "/menu":
-		err = sendMenu(chatId)
+		if sinceEdit >= streamEditEvery {
+			edit := tgbotapi.NewEditMessageText(chatID, placeholder.MessageID, reply.String())
+			if _, err := bot.Send(edit); err != nil {
+				log.Printf("An error occured: %s", err.Error())
+			}
+			sinceEdit = 0
+		}
 	}
 
-	return err
+	final := tgbotapi.NewEditMessageText(chatID, placeholder.MessageID, reply.String())
+	if _, err := bot.Send(final); err != nil {
+		return err
+	}
+
+	sessions.Append(userID, providers.Message{Role: providers.RoleAssistant, Content: reply.String()})
+	return nil
 }
 
-func handleButton(query *tgbotapi.CallbackQuery) {
-	var text string
+func handleProviderButton(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	query := update.CallbackQuery
+	message := query.Message
+	locale := localeFor(query.From.ID, query.From.LanguageCode)
+
+	sessions.SetProvider(query.From.ID, query.Data)
+	bot.Send(tgbotapi.NewCallback(query.ID, translations.T(locale, "provider.selected_short", query.Data)))
+
+	msg := tgbotapi.NewEditMessageText(message.Chat.ID, message.MessageID,
+		translations.T(locale, "provider.selected", query.Data))
+	_, err := bot.Send(msg)
+	return err
+}
 
-	markup := tgbotapi.NewInlineKeyboardMarkup()
+func handleMenuButton(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	query := update.CallbackQuery
 	message := query.Message
+	locale := localeFor(query.From.ID, query.From.LanguageCode)
 
+	var text string
+	var markup tgbotapi.InlineKeyboardMarkup
 	if query.Data == nextButton {
-		text = secondMenu
-		markup = secondMenuMarkup
-	} else if query.Data == backButton {
-		text = firstMenu
-		markup = firstMenuMarkup
+		text = translations.T(locale, "menu.second")
+		markup = secondMenuMarkup(locale)
+	} else {
+		text = translations.T(locale, "menu.first")
+		markup = firstMenuMarkup(locale)
 	}
 
-	callbackCfg := tgbotapi.NewCallback(query.ID, "")
-	bot.Send(callbackCfg)
+	bot.Send(tgbotapi.NewCallback(query.ID, ""))
 
 	// Replace menu text and keyboard
 	msg := tgbotapi.NewEditMessageTextAndMarkup(message.Chat.ID, message.MessageID, text, markup)
 	msg.ParseMode = tgbotapi.ModeHTML
-	bot.Send(msg)
+	_, err := bot.Send(msg)
+	return err
+}
+
+// setupReplyText returns the text a setup Step.Handle should act on,
+// whether the user replied by typing or by tapping an inline button
+// (update.CallbackQuery.Data), so the conversation advances either way.
+func setupReplyText(update tgbotapi.Update) string {
+	switch {
+	case update.Message != nil:
+		return update.Message.Text
+	case update.CallbackQuery != nil:
+		return update.CallbackQuery.Data
+	default:
+		return ""
+	}
+}
+
+// newSetupConversation builds the "/setup" flow: pick a provider, then
+// optionally set a system prompt, before returning control to the
+// normal message handlers. setup.Filter() is registered ahead of the
+// plain provider-button routes in newMux, so tapping startMenuMarkup
+// while this conversation is active advances it instead of falling into
+// handleProviderButton.
+func newSetupConversation() *router.ConversationHandler {
+	pickProvider := router.Step{
+		Name: "pick_provider",
+		Enter: func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+			user := updateUserFrom(update)
+			chatID := updateChatID(update)
+			locale := localeFor(user.ID, user.LanguageCode)
+			msg := tgbotapi.NewMessage(chatID, translations.T(locale, "setup.pick_provider"))
+			msg.ReplyMarkup = startMenuMarkup
+			_, err := bot.Send(msg)
+			return err
+		},
+		Handle: func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update, data map[string]string) (string, error) {
+			user := updateUserFrom(update)
+			chatID := updateChatID(update)
+			locale := localeFor(user.ID, user.LanguageCode)
+
+			choice := strings.TrimSpace(setupReplyText(update))
+			if _, err := registry.Get(choice); err != nil {
+				_, sendErr := bot.Send(tgbotapi.NewMessage(chatID, translations.T(locale, "setup.unknown_provider")))
+				return "pick_provider", sendErr
+			}
+			sessions.SetProvider(user.ID, choice)
+
+			if query := update.CallbackQuery; query != nil {
+				bot.Send(tgbotapi.NewCallback(query.ID, translations.T(locale, "provider.selected_short", choice)))
+			}
+			return "system_prompt", nil
+		},
+	}
+
+	systemPrompt := router.Step{
+		Name: "system_prompt",
+		Enter: func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+			user := updateUserFrom(update)
+			chatID := updateChatID(update)
+			locale := localeFor(user.ID, user.LanguageCode)
+			_, err := bot.Send(tgbotapi.NewMessage(chatID, translations.T(locale, "setup.system_prompt")))
+			return err
+		},
+		Handle: func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update, data map[string]string) (string, error) {
+			user := updateUserFrom(update)
+			chatID := updateChatID(update)
+			locale := localeFor(user.ID, user.LanguageCode)
+
+			if reply := setupReplyText(update); reply != "/skip" {
+				sessions.SetSystem(user.ID, reply)
+			}
+			_, err := bot.Send(tgbotapi.NewMessage(chatID, translations.T(locale, "setup.done")))
+			return "", err
+		},
+	}
+
+	return router.NewConversationHandler(router.NewMemoryStateStore(), pickProvider.Name, pickProvider, systemPrompt)
 }
 
-func sendMenu(chatId int64) error {
-	msg := tgbotapi.NewMessage(chatId, firstMenu)
+func sendMenu(chatId int64, locale string) error {
+	msg := tgbotapi.NewMessage(chatId, translations.T(locale, "menu.first"))
 	msg.ParseMode = tgbotapi.ModeHTML
-	msg.ReplyMarkup = firstMenuMarkup
+	msg.ReplyMarkup = firstMenuMarkup(locale)
 	_, err := bot.Send(msg)
 	return err
 }
 
-func hello(chatId int64, firstName string) error {
-	txt := fmt.Sprintf("سلام %s کون طلا! امروز چی میخوای؟", firstName)
-	msg := tgbotapi.NewMessage(chatId, txt)
+func hello(chatId int64, locale, firstName string) error {
+	msg := tgbotapi.NewMessage(chatId, translations.T(locale, "greeting", firstName))
 	msg.ParseMode = tgbotapi.ModeHTML
 	msg.ReplyMarkup = startMenuMarkup
 	_, err := bot.Send(msg)
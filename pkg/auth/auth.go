@@ -0,0 +1,110 @@
+// Package auth gates access to the bot behind a static allow-list plus
+// a persisted, admin-managed request queue.
+package auth
+
+// Status is a user's access state in the persisted Store.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusAllowed Status = "allowed"
+	StatusDenied  Status = "denied"
+)
+
+// Store persists access decisions beyond the static ALLOWED_USER_IDS
+// list, so admins can /allow or /deny users at runtime. Implementations
+// must be safe for concurrent use.
+type Store interface {
+	Status(userID int64) (Status, error)
+	SetStatus(userID int64, status Status) error
+	Pending() ([]int64, error)
+}
+
+// Authorizer decides whether a Telegram user may use the bot: either
+// they're on the static ALLOWED_USER_IDS/ADMIN_USER_IDS lists baked in
+// at startup, or the Store has recorded them as StatusAllowed.
+type Authorizer struct {
+	allowed map[int64]bool
+	admins  map[int64]bool
+	store   Store
+}
+
+// NewAuthorizer builds an Authorizer. allowedIDs and adminIDs come from
+// ALLOWED_USER_IDS/ADMIN_USER_IDS; every admin is implicitly allowed.
+func NewAuthorizer(allowedIDs, adminIDs []int64, store Store) *Authorizer {
+	a := &Authorizer{
+		allowed: make(map[int64]bool, len(allowedIDs)),
+		admins:  make(map[int64]bool, len(adminIDs)),
+		store:   store,
+	}
+	for _, id := range allowedIDs {
+		a.allowed[id] = true
+	}
+	for _, id := range adminIDs {
+		a.admins[id] = true
+		a.allowed[id] = true
+	}
+	return a
+}
+
+// IsAuthorized reports whether userID may use the bot.
+func (a *Authorizer) IsAuthorized(userID int64) bool {
+	if a.allowed[userID] {
+		return true
+	}
+	status, err := a.store.Status(userID)
+	return err == nil && status == StatusAllowed
+}
+
+// IsAdmin reports whether userID is listed in ADMIN_USER_IDS.
+func (a *Authorizer) IsAdmin(userID int64) bool {
+	return a.admins[userID]
+}
+
+// Admins returns the configured admin user IDs.
+func (a *Authorizer) Admins() []int64 {
+	ids := make([]int64, 0, len(a.admins))
+	for id := range a.admins {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Status reports userID's persisted access status ("" if they've never
+// requested access).
+func (a *Authorizer) Status(userID int64) (Status, error) {
+	return a.store.Status(userID)
+}
+
+// RequestAccess records userID as pending, unless they already have a
+// recorded status (so a repeat message doesn't reset a prior decision).
+// created reports whether this call just created that pending record,
+// so callers can avoid re-notifying admins on every retry.
+func (a *Authorizer) RequestAccess(userID int64) (created bool, err error) {
+	status, err := a.store.Status(userID)
+	if err != nil {
+		return false, err
+	}
+	if status != "" {
+		return false, nil
+	}
+	if err := a.store.SetStatus(userID, StatusPending); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Allow grants userID access.
+func (a *Authorizer) Allow(userID int64) error {
+	return a.store.SetStatus(userID, StatusAllowed)
+}
+
+// Deny revokes/refuses userID access.
+func (a *Authorizer) Deny(userID int64) error {
+	return a.store.SetStatus(userID, StatusDenied)
+}
+
+// Pending lists user IDs awaiting an admin decision.
+func (a *Authorizer) Pending() ([]int64, error) {
+	return a.store.Pending()
+}
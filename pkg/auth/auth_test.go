@@ -0,0 +1,128 @@
+package auth
+
+import "testing"
+
+type memStore struct {
+	status map[int64]Status
+}
+
+func newMemStore() *memStore {
+	return &memStore{status: make(map[int64]Status)}
+}
+
+func (s *memStore) Status(userID int64) (Status, error) {
+	return s.status[userID], nil
+}
+
+func (s *memStore) SetStatus(userID int64, status Status) error {
+	s.status[userID] = status
+	return nil
+}
+
+func (s *memStore) Pending() ([]int64, error) {
+	var ids []int64
+	for id, status := range s.status {
+		if status == StatusPending {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func TestIsAuthorizedStaticAllowList(t *testing.T) {
+	a := NewAuthorizer([]int64{1}, nil, newMemStore())
+
+	if !a.IsAuthorized(1) {
+		t.Errorf("IsAuthorized(1) = false, want true (statically allowed)")
+	}
+	if a.IsAuthorized(2) {
+		t.Errorf("IsAuthorized(2) = true, want false (not allowed anywhere)")
+	}
+}
+
+func TestIsAuthorizedAdminsAreImplicitlyAllowed(t *testing.T) {
+	a := NewAuthorizer(nil, []int64{9}, newMemStore())
+
+	if !a.IsAuthorized(9) {
+		t.Errorf("IsAuthorized(9) = false, want true (admins are implicitly allowed)")
+	}
+}
+
+func TestIsAuthorizedStoreAllowed(t *testing.T) {
+	store := newMemStore()
+	a := NewAuthorizer(nil, nil, store)
+
+	if a.IsAuthorized(5) {
+		t.Fatalf("IsAuthorized(5) = true before any decision, want false")
+	}
+	if err := a.Allow(5); err != nil {
+		t.Fatalf("Allow(5): %v", err)
+	}
+	if !a.IsAuthorized(5) {
+		t.Errorf("IsAuthorized(5) = false after Allow, want true")
+	}
+}
+
+func TestIsAuthorizedStoreDenied(t *testing.T) {
+	store := newMemStore()
+	a := NewAuthorizer(nil, nil, store)
+
+	if err := a.Deny(5); err != nil {
+		t.Fatalf("Deny(5): %v", err)
+	}
+	if a.IsAuthorized(5) {
+		t.Errorf("IsAuthorized(5) = true after Deny, want false")
+	}
+}
+
+func TestRequestAccessOnlyCreatesOnce(t *testing.T) {
+	a := NewAuthorizer(nil, nil, newMemStore())
+
+	created, err := a.RequestAccess(7)
+	if err != nil {
+		t.Fatalf("RequestAccess(7): %v", err)
+	}
+	if !created {
+		t.Errorf("RequestAccess(7) created = false on first call, want true")
+	}
+
+	created, err = a.RequestAccess(7)
+	if err != nil {
+		t.Fatalf("RequestAccess(7) again: %v", err)
+	}
+	if created {
+		t.Errorf("RequestAccess(7) created = true on repeat call, want false")
+	}
+
+	status, err := a.Status(7)
+	if err != nil {
+		t.Fatalf("Status(7): %v", err)
+	}
+	if status != StatusPending {
+		t.Errorf("Status(7) = %q, want %q", status, StatusPending)
+	}
+}
+
+func TestRequestAccessDoesNotOverrideDecision(t *testing.T) {
+	a := NewAuthorizer(nil, nil, newMemStore())
+
+	if err := a.Deny(7); err != nil {
+		t.Fatalf("Deny(7): %v", err)
+	}
+
+	created, err := a.RequestAccess(7)
+	if err != nil {
+		t.Fatalf("RequestAccess(7): %v", err)
+	}
+	if created {
+		t.Errorf("RequestAccess(7) created = true for an already-denied user, want false")
+	}
+
+	status, err := a.Status(7)
+	if err != nil {
+		t.Fatalf("Status(7): %v", err)
+	}
+	if status != StatusDenied {
+		t.Errorf("Status(7) = %q, want %q (RequestAccess must not clobber a prior decision)", status, StatusDenied)
+	}
+}
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"fmt"
+	"strconv"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var accessBucket = []byte("access")
+
+// BoltStore persists access decisions in a BoltDB file so approvals
+// survive restarts.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (creating if necessary) the given BoltDB file and
+// ensures its access bucket exists.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("auth: open bolt db: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(accessBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("auth: init bolt bucket: %w", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// Close releases the underlying BoltDB file.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *BoltStore) Status(userID int64) (Status, error) {
+	var status Status
+	err := s.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(accessBucket).Get(userKey(userID))
+		if raw != nil {
+			status = Status(raw)
+		}
+		return nil
+	})
+	return status, err
+}
+
+func (s *BoltStore) SetStatus(userID int64, status Status) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(accessBucket).Put(userKey(userID), []byte(status))
+	})
+}
+
+func (s *BoltStore) Pending() ([]int64, error) {
+	var ids []int64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(accessBucket).ForEach(func(k, v []byte) error {
+			if Status(v) != StatusPending {
+				return nil
+			}
+			id, err := strconv.ParseInt(string(k), 10, 64)
+			if err != nil {
+				return nil
+			}
+			ids = append(ids, id)
+			return nil
+		})
+	})
+	return ids, err
+}
+
+func userKey(userID int64) []byte {
+	return []byte(strconv.FormatInt(userID, 10))
+}
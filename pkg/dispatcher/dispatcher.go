@@ -0,0 +1,150 @@
+// Package dispatcher fans Telegram updates out to a bounded pool of
+// worker goroutines while preserving per-chat ordering, so a slow
+// handler for one chat (e.g. an LLM call) can't stall every other chat.
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// HandlerFunc processes a single update. Mux.Dispatch also takes the
+// bot, so a Dispatcher typically wraps mux.Dispatch in a closure that
+// supplies it.
+type HandlerFunc func(ctx context.Context, update tgbotapi.Update) error
+
+// laneQueueSize bounds how many updates can back up behind a slow chat
+// before Dispatch starts applying backpressure.
+const laneQueueSize = 64
+
+// Dispatcher shards updates across a fixed number of lanes keyed by
+// chat ID, so all updates for a given chat are handled by the same
+// lane (and therefore in order) while different chats run concurrently.
+type Dispatcher struct {
+	lanes   []chan tgbotapi.Update
+	handle  HandlerFunc
+	onError func(chatID int64, err error)
+}
+
+// Option configures a Dispatcher.
+type Option func(*Dispatcher)
+
+// WithErrorHandler sets the callback invoked (from the owning lane's
+// goroutine) when handle returns an error or panics, so callers can
+// e.g. notify the chat "internal error occurred".
+func WithErrorHandler(f func(chatID int64, err error)) Option {
+	return func(d *Dispatcher) { d.onError = f }
+}
+
+// New builds a Dispatcher with the given number of worker lanes. workers
+// <= 0 defaults to runtime.NumCPU().
+func New(workers int, handle HandlerFunc, opts ...Option) *Dispatcher {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	d := &Dispatcher{
+		lanes:  make([]chan tgbotapi.Update, workers),
+		handle: handle,
+	}
+	for i := range d.lanes {
+		d.lanes[i] = make(chan tgbotapi.Update, laneQueueSize)
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Run starts one goroutine per lane. It returns immediately; the
+// goroutines exit once ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	for i, lane := range d.lanes {
+		go d.runLane(ctx, i, lane)
+	}
+}
+
+// Dispatch enqueues update onto the lane for its chat, blocking if that
+// lane's queue is full (backpressure rather than dropping updates).
+// Updates with no chat (should not normally occur) go to lane 0.
+func (d *Dispatcher) Dispatch(ctx context.Context, update tgbotapi.Update) {
+	lane := d.lanes[d.laneIndex(update)]
+
+	select {
+	case lane <- update:
+	case <-ctx.Done():
+	}
+}
+
+func (d *Dispatcher) laneIndex(update tgbotapi.Update) int {
+	chatID := chatIDOf(update)
+	if chatID == 0 {
+		return 0
+	}
+	// A simple positive modulo; chat IDs are frequently negative for groups.
+	n := int64(len(d.lanes))
+	idx := chatID % n
+	if idx < 0 {
+		idx += n
+	}
+	return int(idx)
+}
+
+func chatIDOf(update tgbotapi.Update) int64 {
+	switch {
+	case update.Message != nil && update.Message.Chat != nil:
+		return update.Message.Chat.ID
+	case update.CallbackQuery != nil && update.CallbackQuery.Message != nil:
+		return update.CallbackQuery.Message.Chat.ID
+	default:
+		return 0
+	}
+}
+
+func (d *Dispatcher) runLane(ctx context.Context, index int, lane chan tgbotapi.Update) {
+	label := strconv.Itoa(index)
+	for {
+		queueDepth.WithLabelValues(label).Set(float64(len(lane)))
+
+		select {
+		case <-ctx.Done():
+			return
+		case update := <-lane:
+			queueDepth.WithLabelValues(label).Set(float64(len(lane)))
+			d.process(ctx, update)
+		}
+	}
+}
+
+func (d *Dispatcher) process(ctx context.Context, update tgbotapi.Update) {
+	inFlight.Inc()
+	defer inFlight.Dec()
+
+	start := time.Now()
+	defer func() { handlerLatency.Observe(time.Since(start).Seconds()) }()
+
+	err := d.safeHandle(ctx, update)
+	if err != nil && d.onError != nil {
+		d.onError(chatIDOf(update), err)
+	}
+}
+
+// safeHandle runs d.handle, converting a panic into an error so one
+// misbehaving update can't take down the whole lane.
+func (d *Dispatcher) safeHandle(ctx context.Context, update tgbotapi.Update) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panics.Inc()
+			log.Printf("dispatcher: recovered panic handling update %d: %v\n%s", update.UpdateID, r, debug.Stack())
+			err = fmt.Errorf("dispatcher: handler panicked: %v", r)
+		}
+	}()
+	return d.handle(ctx, update)
+}
@@ -0,0 +1,40 @@
+package dispatcher
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func messageUpdate(chatID int64) tgbotapi.Update {
+	return tgbotapi.Update{Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: chatID}}}
+}
+
+func TestLaneIndexSameChatIsStable(t *testing.T) {
+	d := New(4, nil)
+
+	first := d.laneIndex(messageUpdate(42))
+	for i := 0; i < 10; i++ {
+		if got := d.laneIndex(messageUpdate(42)); got != first {
+			t.Fatalf("laneIndex(42) = %d on call %d, want %d (same chat must stay on one lane)", got, i, first)
+		}
+	}
+}
+
+func TestLaneIndexNegativeChatID(t *testing.T) {
+	d := New(4, nil)
+
+	// Group chat IDs are negative; the result must still land in range.
+	idx := d.laneIndex(messageUpdate(-1001234567890))
+	if idx < 0 || idx >= len(d.lanes) {
+		t.Fatalf("laneIndex(negative chat) = %d, want [0, %d)", idx, len(d.lanes))
+	}
+}
+
+func TestLaneIndexNoChatDefaultsToZero(t *testing.T) {
+	d := New(4, nil)
+
+	if idx := d.laneIndex(tgbotapi.Update{}); idx != 0 {
+		t.Fatalf("laneIndex(no chat) = %d, want 0", idx)
+	}
+}
@@ -0,0 +1,29 @@
+package dispatcher
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	queueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "telegram_dispatcher_queue_depth",
+		Help: "Number of updates buffered in a per-chat-lane queue.",
+	}, []string{"lane"})
+
+	inFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "telegram_dispatcher_in_flight_handlers",
+		Help: "Number of handler invocations currently executing.",
+	})
+
+	handlerLatency = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "telegram_dispatcher_handler_duration_seconds",
+		Help:    "Time spent inside the update handler.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	panics = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "telegram_dispatcher_handler_panics_total",
+		Help: "Number of handler invocations that recovered from a panic.",
+	})
+)
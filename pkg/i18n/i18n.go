@@ -0,0 +1,134 @@
+// Package i18n loads translation bundles from an i18n/ directory and
+// resolves which one applies to a given Telegram user.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Bundle maps translation keys to message templates (fmt.Sprintf verbs
+// are substituted by T's args).
+type Bundle map[string]string
+
+// Catalog holds every loaded Bundle, keyed by locale, plus per-user
+// locale overrides set via /lang.
+type Catalog struct {
+	mu            sync.RWMutex
+	bundles       map[string]Bundle
+	overrides     map[int64]string
+	defaultLocale string
+}
+
+// Load reads every *.yaml, *.yml and *.json file in dir as a locale
+// bundle named after its filename (en.yaml -> locale "en").
+func Load(dir, defaultLocale string) (*Catalog, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("i18n: read %s: %w", dir, err)
+	}
+
+	c := &Catalog{
+		bundles:       make(map[string]Bundle),
+		overrides:     make(map[int64]string),
+		defaultLocale: defaultLocale,
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext := filepath.Ext(entry.Name())
+		if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+			continue
+		}
+
+		locale := strings.TrimSuffix(entry.Name(), ext)
+		raw, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("i18n: read %s: %w", entry.Name(), err)
+		}
+
+		bundle := make(Bundle)
+		if ext == ".json" {
+			err = json.Unmarshal(raw, &bundle)
+		} else {
+			err = yaml.Unmarshal(raw, &bundle)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("i18n: parse %s: %w", entry.Name(), err)
+		}
+
+		c.bundles[locale] = bundle
+	}
+
+	return c, nil
+}
+
+// Has reports whether locale has a loaded bundle.
+func (c *Catalog) Has(locale string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.bundles[locale]
+	return ok
+}
+
+// SetUserLocale overrides the locale used for userID, e.g. via /lang.
+func (c *Catalog) SetUserLocale(userID int64, locale string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.overrides[userID] = locale
+}
+
+// LocaleFor resolves the locale to use for userID: their /lang
+// override, then their Telegram client's language code, then the
+// catalog's default.
+func (c *Catalog) LocaleFor(userID int64, languageCode string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if override, ok := c.overrides[userID]; ok {
+		return override
+	}
+	if _, ok := c.bundles[languageCode]; ok {
+		return languageCode
+	}
+	// Telegram clients sometimes report a full BCP-47 tag (e.g. "en-US");
+	// fall back to its base language before giving up on it.
+	if base, _, ok := strings.Cut(languageCode, "-"); ok {
+		if _, ok := c.bundles[base]; ok {
+			return base
+		}
+	}
+	return c.defaultLocale
+}
+
+// T renders key in locale, falling back to the catalog's default locale
+// and then to the bare key if no bundle has a translation for it. args
+// are applied with fmt.Sprintf.
+func (c *Catalog) T(locale, key string, args ...interface{}) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	template, ok := c.bundles[locale][key]
+	if !ok {
+		template, ok = c.bundles[c.defaultLocale][key]
+	}
+	if !ok {
+		template = key
+	}
+
+	if len(args) == 0 {
+		return template
+	}
+	return fmt.Sprintf(template, args...)
+}
@@ -0,0 +1,71 @@
+package i18n
+
+import "testing"
+
+func testCatalog() *Catalog {
+	return &Catalog{
+		bundles: map[string]Bundle{
+			"en": {"greeting": "Hi %s!", "only.en": "english only"},
+			"ru": {"greeting": "Привет, %s!"},
+		},
+		overrides:     make(map[int64]string),
+		defaultLocale: "en",
+	}
+}
+
+func TestLocaleForUserOverrideWins(t *testing.T) {
+	c := testCatalog()
+	c.SetUserLocale(1, "ru")
+
+	if got := c.LocaleFor(1, "en"); got != "ru" {
+		t.Errorf("LocaleFor = %q, want %q (override beats language code)", got, "ru")
+	}
+}
+
+func TestLocaleForLanguageCode(t *testing.T) {
+	c := testCatalog()
+
+	if got := c.LocaleFor(2, "ru"); got != "ru" {
+		t.Errorf("LocaleFor = %q, want %q", got, "ru")
+	}
+}
+
+func TestLocaleForBCP47RegionFallsBackToBaseLanguage(t *testing.T) {
+	c := testCatalog()
+
+	if got := c.LocaleFor(3, "ru-RU"); got != "ru" {
+		t.Errorf("LocaleFor(\"ru-RU\") = %q, want %q", got, "ru")
+	}
+}
+
+func TestLocaleForUnknownFallsBackToDefault(t *testing.T) {
+	c := testCatalog()
+
+	if got := c.LocaleFor(4, "fr"); got != "en" {
+		t.Errorf("LocaleFor(\"fr\") = %q, want default %q", got, "en")
+	}
+}
+
+func TestTRendersWithArgs(t *testing.T) {
+	c := testCatalog()
+
+	if got := c.T("ru", "greeting", "Ivan"); got != "Привет, Ivan!" {
+		t.Errorf("T(ru, greeting) = %q, want %q", got, "Привет, Ivan!")
+	}
+}
+
+func TestTFallsBackToDefaultLocale(t *testing.T) {
+	c := testCatalog()
+
+	if got := c.T("ru", "only.en"); got != "english only" {
+		t.Errorf("T(ru, only.en) = %q, want fallback to default locale's %q", got, "english only")
+	}
+}
+
+func TestTFallsBackToBareKey(t *testing.T) {
+	c := testCatalog()
+
+	if got := c.T("ru", "missing.key"); got != "missing.key" {
+		t.Errorf("T(ru, missing.key) = %q, want bare key", got)
+	}
+}
@@ -0,0 +1,11 @@
+package pkg
+
+// Names of the AI providers offered on the start menu. These double as
+// both the button text and the callback data so the router's
+// IsCallbackData routes (and handleProviderButton) can match on them
+// directly.
+const (
+	ChatGPT = "ChatGPT"
+	Claude  = "Claude"
+	Gemini  = "Gemini"
+)
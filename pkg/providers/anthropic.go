@@ -0,0 +1,150 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const anthropicDefaultModel = "claude-3-5-sonnet-latest"
+
+// Anthropic adapts the Messages streaming API.
+type Anthropic struct {
+	APIKey     string
+	BaseURL    string // defaults to https://api.anthropic.com/v1
+	Version    string // defaults to 2023-06-01
+	HTTPClient *http.Client
+	models     []string
+}
+
+// NewAnthropic builds an Anthropic provider. models may be left nil to
+// fall back to a small default set.
+func NewAnthropic(apiKey string, models ...string) *Anthropic {
+	if len(models) == 0 {
+		models = []string{anthropicDefaultModel, "claude-3-5-haiku-latest"}
+	}
+	return &Anthropic{APIKey: apiKey, models: models}
+}
+
+func (a *Anthropic) Name() string     { return "Claude" }
+func (a *Anthropic) Models() []string { return a.models }
+
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	Stream    bool               `json:"stream"`
+	MaxTokens int                `json:"max_tokens"`
+	System    string             `json:"system,omitempty"`
+	Messages  []anthropicMessage `json:"messages"`
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Text string `json:"text"`
+	} `json:"delta"`
+}
+
+func (a *Anthropic) Complete(ctx context.Context, model string, messages []Message) (<-chan Chunk, error) {
+	if model == "" {
+		model = a.models[0]
+	}
+
+	var system string
+	reqMessages := make([]anthropicMessage, 0, len(messages))
+	for _, m := range messages {
+		if m.Role == RoleSystem {
+			system = m.Content
+			continue
+		}
+		reqMessages = append(reqMessages, anthropicMessage{Role: string(m.Role), Content: m.Content})
+	}
+
+	body, err := json.Marshal(anthropicRequest{
+		Model:     model,
+		Stream:    true,
+		MaxTokens: 4096,
+		System:    system,
+		Messages:  reqMessages,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("anthropic: encode request: %w", err)
+	}
+
+	baseURL := a.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com/v1"
+	}
+	version := a.Version
+	if version == "" {
+		version = "2023-06-01"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("anthropic: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", a.APIKey)
+	req.Header.Set("anthropic-version", version)
+
+	client := a.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic: request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("anthropic: unexpected status %s", resp.Status)
+	}
+
+	out := make(chan Chunk)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+			payload := strings.TrimPrefix(line, "data: ")
+
+			var event anthropicStreamEvent
+			if err := json.Unmarshal([]byte(payload), &event); err != nil {
+				continue
+			}
+			if event.Type == "content_block_delta" && event.Delta.Text != "" {
+				select {
+				case out <- Chunk{Content: event.Delta.Text}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if event.Type == "message_stop" {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			select {
+			case out <- Chunk{Done: true, Err: fmt.Errorf("anthropic: read stream: %w", err)}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,59 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAnthropicCompleteStreamsContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("x-api-key"); got != "test-key" {
+			t.Errorf("x-api-key header = %q, want %q", got, "test-key")
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		io := `data: {"type":"content_block_delta","delta":{"text":"Hel"}}
+data: {"type":"content_block_delta","delta":{"text":"lo"}}
+data: {"type":"message_stop"}
+`
+		w.Write([]byte(io))
+	}))
+	defer server.Close()
+
+	a := NewAnthropic("test-key")
+	a.BaseURL = server.URL
+	a.HTTPClient = server.Client()
+
+	chunks, err := a.Complete(context.Background(), "", []Message{{Role: RoleUser, Content: "hi"}})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	var got strings.Builder
+	for c := range chunks {
+		if c.Err != nil {
+			t.Fatalf("chunk error: %v", c.Err)
+		}
+		got.WriteString(c.Content)
+	}
+	if got.String() != "Hello" {
+		t.Errorf("streamed content = %q, want %q", got.String(), "Hello")
+	}
+}
+
+func TestAnthropicCompleteErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	a := NewAnthropic("test-key")
+	a.BaseURL = server.URL
+	a.HTTPClient = server.Client()
+
+	if _, err := a.Complete(context.Background(), "", nil); err == nil {
+		t.Fatal("Complete with 429 response = nil error, want non-nil")
+	}
+}
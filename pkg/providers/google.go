@@ -0,0 +1,143 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const googleDefaultModel = "gemini-1.5-flash"
+
+// Google adapts the Gemini GenAI streaming API.
+type Google struct {
+	APIKey     string
+	BaseURL    string // defaults to https://generativelanguage.googleapis.com/v1beta
+	HTTPClient *http.Client
+	models     []string
+}
+
+// NewGoogle builds a Google GenAI provider. models may be left nil to
+// fall back to a small default set.
+func NewGoogle(apiKey string, models ...string) *Google {
+	if len(models) == 0 {
+		models = []string{googleDefaultModel, "gemini-1.5-pro"}
+	}
+	return &Google{APIKey: apiKey, models: models}
+}
+
+func (g *Google) Name() string     { return "Gemini" }
+func (g *Google) Models() []string { return g.models }
+
+type googleRequest struct {
+	SystemInstruction *googleContent  `json:"systemInstruction,omitempty"`
+	Contents          []googleContent `json:"contents"`
+}
+
+type googleContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []googlePart `json:"parts"`
+}
+
+type googlePart struct {
+	Text string `json:"text"`
+}
+
+type googleStreamChunk struct {
+	Candidates []struct {
+		Content googleContent `json:"content"`
+	} `json:"candidates"`
+}
+
+func (g *Google) Complete(ctx context.Context, model string, messages []Message) (<-chan Chunk, error) {
+	if model == "" {
+		model = g.models[0]
+	}
+
+	req := googleRequest{}
+	for _, m := range messages {
+		if m.Role == RoleSystem {
+			req.SystemInstruction = &googleContent{Parts: []googlePart{{Text: m.Content}}}
+			continue
+		}
+		role := "user"
+		if m.Role == RoleAssistant {
+			role = "model"
+		}
+		req.Contents = append(req.Contents, googleContent{Role: role, Parts: []googlePart{{Text: m.Content}}})
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("google: encode request: %w", err)
+	}
+
+	baseURL := g.BaseURL
+	if baseURL == "" {
+		baseURL = "https://generativelanguage.googleapis.com/v1beta"
+	}
+	url := fmt.Sprintf("%s/models/%s:streamGenerateContent?alt=sse&key=%s", baseURL, model, g.APIKey)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("google: build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	client := g.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("google: request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("google: unexpected status %s", resp.Status)
+	}
+
+	out := make(chan Chunk)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+			payload := strings.TrimPrefix(line, "data: ")
+
+			var chunk googleStreamChunk
+			if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+				continue
+			}
+			for _, candidate := range chunk.Candidates {
+				for _, part := range candidate.Content.Parts {
+					if part.Text == "" {
+						continue
+					}
+					select {
+					case out <- Chunk{Content: part.Text}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			select {
+			case out <- Chunk{Done: true, Err: fmt.Errorf("google: read stream: %w", err)}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,55 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGoogleCompleteStreamsContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io := `data: {"candidates":[{"content":{"parts":[{"text":"Hel"}]}}]}
+data: {"candidates":[{"content":{"parts":[{"text":"lo"}]}}]}
+`
+		w.Write([]byte(io))
+	}))
+	defer server.Close()
+
+	g := NewGoogle("test-key")
+	g.BaseURL = server.URL
+	g.HTTPClient = server.Client()
+
+	chunks, err := g.Complete(context.Background(), "", []Message{{Role: RoleUser, Content: "hi"}})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	var got strings.Builder
+	for c := range chunks {
+		if c.Err != nil {
+			t.Fatalf("chunk error: %v", c.Err)
+		}
+		got.WriteString(c.Content)
+	}
+	if got.String() != "Hello" {
+		t.Errorf("streamed content = %q, want %q", got.String(), "Hello")
+	}
+}
+
+func TestGoogleCompleteErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	g := NewGoogle("test-key")
+	g.BaseURL = server.URL
+	g.HTTPClient = server.Client()
+
+	if _, err := g.Complete(context.Background(), "", nil); err == nil {
+		t.Fatal("Complete with 500 response = nil error, want non-nil")
+	}
+}
@@ -0,0 +1,135 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const openAIDefaultModel = "gpt-4o-mini"
+
+// OpenAI adapts the Chat Completions streaming API.
+type OpenAI struct {
+	APIKey     string
+	BaseURL    string // defaults to https://api.openai.com/v1
+	HTTPClient *http.Client
+	models     []string
+}
+
+// NewOpenAI builds an OpenAI provider. models may be left nil to fall
+// back to a small default set.
+func NewOpenAI(apiKey string, models ...string) *OpenAI {
+	if len(models) == 0 {
+		models = []string{openAIDefaultModel, "gpt-4o"}
+	}
+	return &OpenAI{APIKey: apiKey, models: models}
+}
+
+func (o *OpenAI) Name() string     { return "ChatGPT" }
+func (o *OpenAI) Models() []string { return o.models }
+
+type openAIChatRequest struct {
+	Model    string              `json:"model"`
+	Stream   bool                `json:"stream"`
+	Messages []openAIChatMessage `json:"messages"`
+}
+
+type openAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+func (o *OpenAI) Complete(ctx context.Context, model string, messages []Message) (<-chan Chunk, error) {
+	if model == "" {
+		model = o.models[0]
+	}
+
+	reqMessages := make([]openAIChatMessage, len(messages))
+	for i, m := range messages {
+		reqMessages[i] = openAIChatMessage{Role: string(m.Role), Content: m.Content}
+	}
+
+	body, err := json.Marshal(openAIChatRequest{Model: model, Stream: true, Messages: reqMessages})
+	if err != nil {
+		return nil, fmt.Errorf("openai: encode request: %w", err)
+	}
+
+	baseURL := o.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("openai: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+o.APIKey)
+
+	client := o.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai: request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("openai: unexpected status %s", resp.Status)
+	}
+
+	out := make(chan Chunk)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+			payload := strings.TrimPrefix(line, "data: ")
+			if payload == "[DONE]" {
+				return
+			}
+
+			var chunk openAIStreamChunk
+			if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+				continue
+			}
+			for _, choice := range chunk.Choices {
+				if choice.Delta.Content != "" {
+					select {
+					case out <- Chunk{Content: choice.Delta.Content}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			select {
+			case out <- Chunk{Done: true, Err: fmt.Errorf("openai: read stream: %w", err)}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,90 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpenAICompleteStreamsContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Authorization header = %q, want %q", got, "Bearer test-key")
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		io := `data: {"choices":[{"delta":{"content":"Hel"}}]}
+data: {"choices":[{"delta":{"content":"lo"}}]}
+data: [DONE]
+`
+		w.Write([]byte(io))
+	}))
+	defer server.Close()
+
+	o := NewOpenAI("test-key")
+	o.BaseURL = server.URL
+	o.HTTPClient = server.Client()
+
+	chunks, err := o.Complete(context.Background(), "", []Message{{Role: RoleUser, Content: "hi"}})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	var got strings.Builder
+	for c := range chunks {
+		if c.Err != nil {
+			t.Fatalf("chunk error: %v", c.Err)
+		}
+		got.WriteString(c.Content)
+	}
+	if got.String() != "Hello" {
+		t.Errorf("streamed content = %q, want %q", got.String(), "Hello")
+	}
+}
+
+func TestOpenAICompleteErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	o := NewOpenAI("bad-key")
+	o.BaseURL = server.URL
+	o.HTTPClient = server.Client()
+
+	if _, err := o.Complete(context.Background(), "", nil); err == nil {
+		t.Fatal("Complete with 401 response = nil error, want non-nil")
+	}
+}
+
+func TestOpenAICompleteStopsOnContextCancel(t *testing.T) {
+	blockUntilClosed := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"x\"}}]}\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+		close(blockUntilClosed)
+	}))
+	defer server.Close()
+
+	o := NewOpenAI("test-key")
+	o.BaseURL = server.URL
+	o.HTTPClient = server.Client()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	chunks, err := o.Complete(ctx, "", nil)
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	<-chunks // first chunk
+	cancel()
+
+	select {
+	case <-blockUntilClosed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("server never observed request cancellation")
+	}
+}
@@ -0,0 +1,80 @@
+// Package providers defines a small abstraction over the chat-completion
+// APIs of the LLM vendors this bot talks to, so the rest of the
+// application can treat ChatGPT, Claude and Gemini interchangeably.
+package providers
+
+import "context"
+
+// Role identifies who authored a Message in a conversation.
+type Role string
+
+const (
+	RoleSystem    Role = "system"
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+// Message is a single turn in a conversation, vendor-agnostic.
+type Message struct {
+	Role    Role
+	Content string
+}
+
+// Chunk is one piece of a streamed completion. Err is set (and Done is
+// true) when the stream ends abnormally; a well-behaved stream closes
+// its channel instead of sending a final error chunk.
+type Chunk struct {
+	Content string
+	Done    bool
+	Err     error
+}
+
+// Provider is implemented by each vendor adapter (OpenAI, Anthropic,
+// Google GenAI, ...). Complete streams the assistant's reply to the
+// given conversation one token/delta at a time on the returned channel;
+// the channel is closed when the response is complete or ctx is
+// cancelled.
+type Provider interface {
+	// Name is the vendor-facing identifier, e.g. "ChatGPT", "Claude".
+	Name() string
+
+	// Models lists the model identifiers this provider supports. The
+	// first entry is the default used by new sessions.
+	Models() []string
+
+	// Complete streams a completion for the given conversation using
+	// model. An empty model selects the provider's default.
+	Complete(ctx context.Context, model string, messages []Message) (<-chan Chunk, error)
+}
+
+// ErrUnknownProvider is returned by Registry.Get for an unrecognised name.
+type ErrUnknownProvider string
+
+func (e ErrUnknownProvider) Error() string {
+	return "providers: unknown provider " + string(e)
+}
+
+// Registry looks providers up by the same name used for the start-menu
+// buttons (pkg.ChatGPT, pkg.Claude, pkg.Gemini).
+type Registry struct {
+	byName map[string]Provider
+}
+
+// NewRegistry builds a Registry from the given providers, keyed by
+// Provider.Name().
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{byName: make(map[string]Provider, len(providers))}
+	for _, p := range providers {
+		r.byName[p.Name()] = p
+	}
+	return r
+}
+
+// Get returns the provider registered under name, or ErrUnknownProvider.
+func (r *Registry) Get(name string) (Provider, error) {
+	p, ok := r.byName[name]
+	if !ok {
+		return nil, ErrUnknownProvider(name)
+	}
+	return p, nil
+}
@@ -0,0 +1,40 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type fakeProvider struct{ name string }
+
+func (f fakeProvider) Name() string     { return f.name }
+func (f fakeProvider) Models() []string { return []string{"default"} }
+func (f fakeProvider) Complete(ctx context.Context, model string, messages []Message) (<-chan Chunk, error) {
+	return nil, nil
+}
+
+func TestRegistryGetKnownProvider(t *testing.T) {
+	r := NewRegistry(fakeProvider{name: "ChatGPT"}, fakeProvider{name: "Claude"})
+
+	p, err := r.Get("Claude")
+	if err != nil {
+		t.Fatalf("Get(Claude): %v", err)
+	}
+	if p.Name() != "Claude" {
+		t.Errorf("Get(Claude).Name() = %q, want %q", p.Name(), "Claude")
+	}
+}
+
+func TestRegistryGetUnknownProvider(t *testing.T) {
+	r := NewRegistry(fakeProvider{name: "ChatGPT"})
+
+	_, err := r.Get("Gemini")
+	if err == nil {
+		t.Fatal("Get(Gemini) = nil error, want ErrUnknownProvider")
+	}
+	var unknown ErrUnknownProvider
+	if !errors.As(err, &unknown) {
+		t.Errorf("Get(Gemini) error = %v (%T), want ErrUnknownProvider", err, err)
+	}
+}
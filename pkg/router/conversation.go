@@ -0,0 +1,147 @@
+package router
+
+import (
+	"context"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ConversationState is one user's position within a ConversationHandler,
+// persisted between updates.
+type ConversationState struct {
+	Step string
+	Data map[string]string
+}
+
+// StateStore persists per-user ConversationState. Implementations must
+// be safe for concurrent use.
+type StateStore interface {
+	Get(userID int64) (ConversationState, bool, error)
+	Set(userID int64, state ConversationState) error
+	Clear(userID int64) error
+}
+
+// MemoryStateStore is the default, non-persistent StateStore.
+type MemoryStateStore struct {
+	mu     sync.Mutex
+	states map[int64]ConversationState
+}
+
+// NewMemoryStateStore returns an empty MemoryStateStore.
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{states: make(map[int64]ConversationState)}
+}
+
+func (s *MemoryStateStore) Get(userID int64) (ConversationState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[userID]
+	return state, ok, nil
+}
+
+func (s *MemoryStateStore) Set(userID int64, state ConversationState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.states[userID] = state
+	return nil
+}
+
+func (s *MemoryStateStore) Clear(userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.states, userID)
+	return nil
+}
+
+// Step is one stage of a multi-step conversation, e.g. "pick provider",
+// "enter system prompt", "chat".
+type Step struct {
+	// Name identifies the step and is what gets persisted in StateStore.
+	Name string
+
+	// Enter sends the prompt for this step, e.g. "Which provider?".
+	Enter HandlerFunc
+
+	// Handle processes the user's reply to this step's prompt and
+	// returns the name of the next step, or "" to end the conversation.
+	Handle func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update, data map[string]string) (next string, err error)
+}
+
+// ConversationHandler walks a user through a fixed sequence of Steps,
+// persisting progress in a StateStore so it survives process restarts
+// when backed by something other than MemoryStateStore.
+type ConversationHandler struct {
+	store StateStore
+	start string
+	steps map[string]Step
+}
+
+// NewConversationHandler builds a ConversationHandler beginning at the
+// step named start.
+func NewConversationHandler(store StateStore, start string, steps ...Step) *ConversationHandler {
+	byName := make(map[string]Step, len(steps))
+	for _, step := range steps {
+		byName[step.Name] = step
+	}
+	return &ConversationHandler{store: store, start: start, steps: byName}
+}
+
+// Active reports whether userID currently has an in-progress conversation.
+func (c *ConversationHandler) Active(userID int64) bool {
+	_, ok, err := c.store.Get(userID)
+	return ok && err == nil
+}
+
+// Filter matches updates from users with an in-progress conversation.
+func (c *ConversationHandler) Filter() Filter {
+	return func(update tgbotapi.Update) bool {
+		user := updateUser(update)
+		return user != nil && c.Active(user.ID)
+	}
+}
+
+// Start begins the conversation for userID at its first step.
+func (c *ConversationHandler) Start(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	user := updateUser(update)
+	if err := c.store.Set(user.ID, ConversationState{Step: c.start, Data: map[string]string{}}); err != nil {
+		return err
+	}
+	return c.steps[c.start].Enter(ctx, bot, update)
+}
+
+// Handler returns the HandlerFunc to register on a Mux (guarded by
+// Filter) that advances the conversation on each reply.
+func (c *ConversationHandler) Handler() HandlerFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		user := updateUser(update)
+
+		state, ok, err := c.store.Get(user.ID)
+		if err != nil || !ok {
+			return err
+		}
+
+		step, ok := c.steps[state.Step]
+		if !ok {
+			return c.store.Clear(user.ID)
+		}
+
+		next, err := step.Handle(ctx, bot, update, state.Data)
+		if err != nil {
+			return err
+		}
+
+		if next == "" {
+			return c.store.Clear(user.ID)
+		}
+
+		state.Step = next
+		if err := c.store.Set(user.ID, state); err != nil {
+			return err
+		}
+		return c.steps[next].Enter(ctx, bot, update)
+	}
+}
@@ -0,0 +1,115 @@
+package router
+
+import (
+	"context"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestConversationHandlerAdvancesThroughSteps(t *testing.T) {
+	var entered []string
+	steps := []Step{
+		{
+			Name: "first",
+			Enter: func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+				entered = append(entered, "first")
+				return nil
+			},
+			Handle: func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update, data map[string]string) (string, error) {
+				data["answer"] = "yes"
+				return "second", nil
+			},
+		},
+		{
+			Name: "second",
+			Enter: func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+				entered = append(entered, "second")
+				return nil
+			},
+			Handle: func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update, data map[string]string) (string, error) {
+				if data["answer"] != "yes" {
+					t.Errorf("data[\"answer\"] = %q, want carried over from step one", data["answer"])
+				}
+				return "", nil
+			},
+		},
+	}
+
+	c := NewConversationHandler(NewMemoryStateStore(), "first", steps...)
+	user := &tgbotapi.User{ID: 1}
+	update := tgbotapi.Update{Message: &tgbotapi.Message{From: user}}
+
+	if err := c.Start(context.Background(), nil, update); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if !c.Active(1) {
+		t.Fatal("Active(1) = false after Start")
+	}
+
+	if err := c.Handler()(context.Background(), nil, update); err != nil {
+		t.Fatalf("Handler (step one -> two): %v", err)
+	}
+	if !c.Active(1) {
+		t.Fatal("Active(1) = false mid-conversation")
+	}
+
+	if err := c.Handler()(context.Background(), nil, update); err != nil {
+		t.Fatalf("Handler (step two -> end): %v", err)
+	}
+	if c.Active(1) {
+		t.Error("Active(1) = true after final step returned empty next")
+	}
+
+	want := []string{"first", "second"}
+	if len(entered) != len(want) || entered[0] != want[0] || entered[1] != want[1] {
+		t.Errorf("entered = %v, want %v", entered, want)
+	}
+}
+
+func TestConversationHandlerFilterOnlyMatchesActiveUsers(t *testing.T) {
+	steps := []Step{{
+		Name:  "only",
+		Enter: func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error { return nil },
+		Handle: func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update, data map[string]string) (string, error) {
+			return "", nil
+		},
+	}}
+	c := NewConversationHandler(NewMemoryStateStore(), "only", steps...)
+
+	update := tgbotapi.Update{Message: &tgbotapi.Message{From: &tgbotapi.User{ID: 1}}}
+	if c.Filter()(update) {
+		t.Error("Filter matched a user with no active conversation")
+	}
+
+	if err := c.Start(context.Background(), nil, update); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if !c.Filter()(update) {
+		t.Error("Filter rejected a user with an active conversation")
+	}
+}
+
+func TestMemoryStateStoreGetSetClear(t *testing.T) {
+	store := NewMemoryStateStore()
+
+	if _, ok, _ := store.Get(1); ok {
+		t.Fatal("Get on empty store returned ok=true")
+	}
+
+	state := ConversationState{Step: "first", Data: map[string]string{"k": "v"}}
+	if err := store.Set(1, state); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	got, ok, err := store.Get(1)
+	if err != nil || !ok || got.Step != "first" {
+		t.Fatalf("Get after Set = %v, %v, %v", got, ok, err)
+	}
+
+	if err := store.Clear(1); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if _, ok, _ := store.Get(1); ok {
+		t.Error("Get after Clear returned ok=true")
+	}
+}
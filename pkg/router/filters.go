@@ -0,0 +1,86 @@
+package router
+
+import (
+	"regexp"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// IsCommand matches a message update whose text is the given command,
+// e.g. "start" matches both "/start" and "/start@YourBot".
+func IsCommand(name string) Filter {
+	return func(update tgbotapi.Update) bool {
+		if update.Message == nil || !update.Message.IsCommand() {
+			return false
+		}
+		return update.Message.Command() == name
+	}
+}
+
+// IsCallbackData matches a callback query whose data starts with prefix.
+func IsCallbackData(prefix string) Filter {
+	return func(update tgbotapi.Update) bool {
+		if update.CallbackQuery == nil {
+			return false
+		}
+		return strings.HasPrefix(update.CallbackQuery.Data, prefix)
+	}
+}
+
+// IsPrivateChat matches a message sent in a one-on-one chat with the bot.
+func IsPrivateChat() Filter {
+	return func(update tgbotapi.Update) bool {
+		return update.Message != nil && update.Message.Chat != nil && update.Message.Chat.IsPrivate()
+	}
+}
+
+// HasText matches a message whose text matches the given regular expression.
+func HasText(re *regexp.Regexp) Filter {
+	return func(update tgbotapi.Update) bool {
+		return update.Message != nil && re.MatchString(update.Message.Text)
+	}
+}
+
+// FromUserID matches an update (message or callback) sent by id.
+func FromUserID(id int64) Filter {
+	return func(update tgbotapi.Update) bool {
+		user := updateUser(update)
+		return user != nil && user.ID == id
+	}
+}
+
+// All matches when every given filter matches.
+func All(filters ...Filter) Filter {
+	return func(update tgbotapi.Update) bool {
+		for _, f := range filters {
+			if !f(update) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Any matches when at least one given filter matches.
+func Any(filters ...Filter) Filter {
+	return func(update tgbotapi.Update) bool {
+		for _, f := range filters {
+			if f(update) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func updateUser(update tgbotapi.Update) *tgbotapi.User {
+	switch {
+	case update.Message != nil:
+		return update.Message.From
+	case update.CallbackQuery != nil:
+		return update.CallbackQuery.From
+	default:
+		return nil
+	}
+}
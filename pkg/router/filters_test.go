@@ -0,0 +1,94 @@
+package router
+
+import (
+	"regexp"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestIsCommandMatchesExactName(t *testing.T) {
+	f := IsCommand("start")
+
+	if !f(commandUpdate("start")) {
+		t.Error("IsCommand(\"start\") rejected /start")
+	}
+	if f(commandUpdate("menu")) {
+		t.Error("IsCommand(\"start\") accepted /menu")
+	}
+	if f(tgbotapi.Update{Message: &tgbotapi.Message{Text: "start"}}) {
+		t.Error("IsCommand(\"start\") accepted plain text with no leading slash")
+	}
+}
+
+func TestIsCallbackDataMatchesPrefix(t *testing.T) {
+	f := IsCallbackData("setup:")
+
+	match := tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{Data: "setup:provider:chatgpt"}}
+	if !f(match) {
+		t.Error("IsCallbackData(\"setup:\") rejected matching callback data")
+	}
+
+	noMatch := tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{Data: "other:thing"}}
+	if f(noMatch) {
+		t.Error("IsCallbackData(\"setup:\") accepted non-matching callback data")
+	}
+
+	if f(tgbotapi.Update{}) {
+		t.Error("IsCallbackData(\"setup:\") accepted update with no CallbackQuery")
+	}
+}
+
+func TestHasTextMatchesRegexp(t *testing.T) {
+	f := HasText(regexp.MustCompile(`^hi\b`))
+
+	if !f(tgbotapi.Update{Message: &tgbotapi.Message{Text: "hi there"}}) {
+		t.Error("HasText rejected matching message")
+	}
+	if f(tgbotapi.Update{Message: &tgbotapi.Message{Text: "bye"}}) {
+		t.Error("HasText accepted non-matching message")
+	}
+}
+
+func TestFromUserIDMatchesSenderAcrossUpdateKinds(t *testing.T) {
+	f := FromUserID(42)
+
+	msg := tgbotapi.Update{Message: &tgbotapi.Message{From: &tgbotapi.User{ID: 42}}}
+	if !f(msg) {
+		t.Error("FromUserID rejected matching message sender")
+	}
+
+	cb := tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{From: &tgbotapi.User{ID: 42}}}
+	if !f(cb) {
+		t.Error("FromUserID rejected matching callback sender")
+	}
+
+	other := tgbotapi.Update{Message: &tgbotapi.Message{From: &tgbotapi.User{ID: 7}}}
+	if f(other) {
+		t.Error("FromUserID accepted a different sender")
+	}
+}
+
+func TestAllRequiresEveryFilter(t *testing.T) {
+	alwaysTrue := func(update tgbotapi.Update) bool { return true }
+	alwaysFalse := func(update tgbotapi.Update) bool { return false }
+
+	if !All(alwaysTrue, alwaysTrue)(tgbotapi.Update{}) {
+		t.Error("All(true, true) = false, want true")
+	}
+	if All(alwaysTrue, alwaysFalse)(tgbotapi.Update{}) {
+		t.Error("All(true, false) = true, want false")
+	}
+}
+
+func TestAnyRequiresOneFilter(t *testing.T) {
+	alwaysTrue := func(update tgbotapi.Update) bool { return true }
+	alwaysFalse := func(update tgbotapi.Update) bool { return false }
+
+	if !Any(alwaysFalse, alwaysTrue)(tgbotapi.Update{}) {
+		t.Error("Any(false, true) = false, want true")
+	}
+	if Any(alwaysFalse, alwaysFalse)(tgbotapi.Update{}) {
+		t.Error("Any(false, false) = true, want false")
+	}
+}
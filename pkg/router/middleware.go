@@ -0,0 +1,63 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Logging logs every update that reaches a handler, along with the
+// handler's error (if any), to logger.
+func Logging(logger *log.Logger) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+			user := updateUser(update)
+			err := next(ctx, bot, update)
+			if err != nil {
+				logger.Printf("router: update %d from user %v: %s", update.UpdateID, userID(user), err)
+			} else {
+				logger.Printf("router: update %d from user %v handled", update.UpdateID, userID(user))
+			}
+			return err
+		}
+	}
+}
+
+// Recover wraps next so a panic inside it is logged and converted to an
+// error instead of crashing the update-processing goroutine.
+func Recover(logger *log.Logger) Middleware {
+	return func(next HandlerFunc) (handler HandlerFunc) {
+		return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Printf("router: recovered panic handling update %d: %v", update.UpdateID, r)
+					err = fmt.Errorf("router: handler panicked: %v", r)
+				}
+			}()
+			return next(ctx, bot, update)
+		}
+	}
+}
+
+// Authorize short-circuits the handler chain with deny's error unless
+// allowed reports true for the update's sender.
+func Authorize(allowed func(userID int64) bool, deny HandlerFunc) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+			user := updateUser(update)
+			if user == nil || !allowed(user.ID) {
+				return deny(ctx, bot, update)
+			}
+			return next(ctx, bot, update)
+		}
+	}
+}
+
+func userID(user *tgbotapi.User) int64 {
+	if user == nil {
+		return 0
+	}
+	return user.ID
+}
@@ -0,0 +1,77 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestRecoverConvertsPanicToError(t *testing.T) {
+	logger := log.New(io.Discard, "", 0)
+	handler := Recover(logger)(func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		panic("boom")
+	})
+
+	err := handler(context.Background(), nil, tgbotapi.Update{})
+	if err == nil {
+		t.Fatal("Recover swallowed a panic without returning an error")
+	}
+}
+
+func TestRecoverPassesThroughNormalError(t *testing.T) {
+	logger := log.New(io.Discard, "", 0)
+	wantErr := errors.New("handler failed")
+	handler := Recover(logger)(func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		return wantErr
+	})
+
+	if err := handler(context.Background(), nil, tgbotapi.Update{}); err != wantErr {
+		t.Errorf("Recover err = %v, want %v", err, wantErr)
+	}
+}
+
+func TestAuthorizeAllowsPermittedUser(t *testing.T) {
+	var ran bool
+	denied := false
+	mw := Authorize(func(userID int64) bool { return userID == 1 }, func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		denied = true
+		return nil
+	})
+	handler := mw(func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		ran = true
+		return nil
+	})
+
+	update := tgbotapi.Update{Message: &tgbotapi.Message{From: &tgbotapi.User{ID: 1}}}
+	if err := handler(context.Background(), nil, update); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	if !ran || denied {
+		t.Errorf("ran = %v, denied = %v, want ran=true denied=false", ran, denied)
+	}
+}
+
+func TestAuthorizeDeniesOtherUser(t *testing.T) {
+	var ran bool
+	denied := false
+	mw := Authorize(func(userID int64) bool { return userID == 1 }, func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		denied = true
+		return nil
+	})
+	handler := mw(func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		ran = true
+		return nil
+	})
+
+	update := tgbotapi.Update{Message: &tgbotapi.Message{From: &tgbotapi.User{ID: 2}}}
+	if err := handler(context.Background(), nil, update); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	if ran || !denied {
+		t.Errorf("ran = %v, denied = %v, want ran=false denied=true", ran, denied)
+	}
+}
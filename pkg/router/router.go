@@ -0,0 +1,88 @@
+// Package router is a small telemux-style dispatcher for
+// go-telegram-bot-api updates: routes are filter-matched handler
+// functions, composable into groups with their own middleware stack.
+package router
+
+import (
+	"context"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// HandlerFunc handles a single matched update.
+type HandlerFunc func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error
+
+// Filter reports whether update should be routed to a given handler.
+type Filter func(update tgbotapi.Update) bool
+
+// Middleware wraps a HandlerFunc, e.g. to log, authorize or recover
+// panics before/after the wrapped handler runs.
+type Middleware func(HandlerFunc) HandlerFunc
+
+type route struct {
+	filter  Filter
+	handler HandlerFunc
+}
+
+// Mux dispatches updates to the first registered route whose filter
+// matches. Groups nest under a parent, inheriting its middleware.
+type Mux struct {
+	parent     *Mux
+	middleware []Middleware
+	routes     []route
+}
+
+// New returns an empty, top-level Mux.
+func New() *Mux {
+	return &Mux{}
+}
+
+// Use appends middleware applied to every route registered on this Mux
+// (and, since Group inherits routes via Dispatch, indirectly to nested
+// groups too). Middleware added after Handle still wraps that route,
+// since wrapping happens at dispatch time.
+func (m *Mux) Use(mw Middleware) {
+	m.middleware = append(m.middleware, mw)
+}
+
+// Handle registers handler for updates matching filter. Routes are
+// tried in registration order; the first match wins.
+func (m *Mux) Handle(filter Filter, handler HandlerFunc) {
+	m.routes = append(m.routes, route{filter: filter, handler: handler})
+}
+
+// Group returns a new Mux whose routes are tried as a single unit
+// wherever the group itself is registered via Handle, letting related
+// routes share a filter prefix and middleware stack.
+func (m *Mux) Group(filter Filter) *Mux {
+	child := &Mux{parent: m}
+	m.Handle(filter, child.dispatchAsHandler)
+	return child
+}
+
+func (m *Mux) dispatchAsHandler(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	return m.Dispatch(ctx, bot, update)
+}
+
+// ErrNoRoute is returned by Dispatch when no registered route matches.
+type ErrNoRoute struct{}
+
+func (ErrNoRoute) Error() string { return "router: no route matched update" }
+
+// Dispatch runs the first matching route's handler, wrapped in this
+// Mux's middleware stack (outermost first). It returns ErrNoRoute if
+// nothing matches so callers can fall back to default behaviour.
+func (m *Mux) Dispatch(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	for _, r := range m.routes {
+		if !r.filter(update) {
+			continue
+		}
+
+		handler := r.handler
+		for i := len(m.middleware) - 1; i >= 0; i-- {
+			handler = m.middleware[i](handler)
+		}
+		return handler(ctx, bot, update)
+	}
+	return ErrNoRoute{}
+}
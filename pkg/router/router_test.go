@@ -0,0 +1,105 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func commandUpdate(name string) tgbotapi.Update {
+	return tgbotapi.Update{Message: &tgbotapi.Message{
+		Text:     "/" + name,
+		Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(name) + 1}},
+	}}
+}
+
+func TestDispatchRunsFirstMatchingRoute(t *testing.T) {
+	mux := New()
+	var ran string
+	mux.Handle(IsCommand("start"), func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		ran = "start"
+		return nil
+	})
+	mux.Handle(IsCommand("menu"), func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		ran = "menu"
+		return nil
+	})
+
+	if err := mux.Dispatch(context.Background(), nil, commandUpdate("menu")); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+	if ran != "menu" {
+		t.Errorf("handler ran = %q, want %q", ran, "menu")
+	}
+}
+
+func TestDispatchNoMatchReturnsErrNoRoute(t *testing.T) {
+	mux := New()
+	mux.Handle(IsCommand("start"), func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		return nil
+	})
+
+	err := mux.Dispatch(context.Background(), nil, commandUpdate("menu"))
+	var noRoute ErrNoRoute
+	if !errors.As(err, &noRoute) {
+		t.Errorf("Dispatch(unmatched) error = %v, want ErrNoRoute", err)
+	}
+}
+
+func TestUseWrapsHandlerOutermostFirst(t *testing.T) {
+	mux := New()
+	var order []string
+	mux.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+			order = append(order, "mw1-before")
+			err := next(ctx, bot, update)
+			order = append(order, "mw1-after")
+			return err
+		}
+	})
+	mux.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+			order = append(order, "mw2-before")
+			err := next(ctx, bot, update)
+			order = append(order, "mw2-after")
+			return err
+		}
+	})
+	mux.Handle(IsCommand("start"), func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		order = append(order, "handler")
+		return nil
+	})
+
+	if err := mux.Dispatch(context.Background(), nil, commandUpdate("start")); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+
+	want := []string{"mw1-before", "mw2-before", "handler", "mw2-after", "mw1-after"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestGroupDispatchesNestedRoutes(t *testing.T) {
+	mux := New()
+	group := mux.Group(IsCommand("admin"))
+	var ran bool
+	group.Handle(func(update tgbotapi.Update) bool { return true }, func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		ran = true
+		return nil
+	})
+
+	if err := mux.Dispatch(context.Background(), nil, commandUpdate("admin")); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+	if !ran {
+		t.Error("group's nested handler never ran")
+	}
+}
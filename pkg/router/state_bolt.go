@@ -0,0 +1,77 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var conversationBucket = []byte("conversations")
+
+// BoltStateStore persists ConversationState in a BoltDB file, so
+// in-progress conversations survive process restarts.
+type BoltStateStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStateStore opens (creating if necessary) the given BoltDB file
+// and ensures its conversation bucket exists.
+func NewBoltStateStore(path string) (*BoltStateStore, error) {
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("router: open bolt db: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(conversationBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("router: init bolt bucket: %w", err)
+	}
+
+	return &BoltStateStore{db: db}, nil
+}
+
+// Close releases the underlying BoltDB file.
+func (s *BoltStateStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *BoltStateStore) Get(userID int64) (ConversationState, bool, error) {
+	var state ConversationState
+	var found bool
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(conversationBucket).Get(userKey(userID))
+		if raw == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(raw, &state)
+	})
+	return state, found, err
+}
+
+func (s *BoltStateStore) Set(userID int64, state ConversationState) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("router: encode conversation state: %w", err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(conversationBucket).Put(userKey(userID), raw)
+	})
+}
+
+func (s *BoltStateStore) Clear(userID int64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(conversationBucket).Delete(userKey(userID))
+	})
+}
+
+func userKey(userID int64) []byte {
+	return []byte(fmt.Sprintf("%d", userID))
+}
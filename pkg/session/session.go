@@ -0,0 +1,138 @@
+// Package session tracks, per Telegram user, which AI provider they've
+// selected, the conversation so far, and the system prompt to use.
+package session
+
+import (
+	"sync"
+
+	"ai-gateway-telegram/pkg/providers"
+)
+
+const defaultSystemPrompt = "You are a helpful assistant."
+
+// Session holds one user's conversation state. Exported accessor and
+// mutator methods serialize access via mu, since a single user's chats
+// in different lanes of the dispatcher's worker pool can reach the same
+// Session concurrently.
+type Session struct {
+	mu       sync.RWMutex
+	provider string
+	model    string
+	system   string
+	history  []providers.Message
+}
+
+// Provider returns the session's currently selected provider, or "" if
+// none has been picked yet.
+func (s *Session) Provider() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.provider
+}
+
+// Model returns the model override for the session's current provider,
+// or "" to use that provider's default.
+func (s *Session) Model() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.model
+}
+
+// Messages returns the conversation to send to the provider: the system
+// prompt followed by history.
+func (s *Session) Messages() []providers.Message {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	msgs := make([]providers.Message, 0, len(s.history)+1)
+	if s.system != "" {
+		msgs = append(msgs, providers.Message{Role: providers.RoleSystem, Content: s.system})
+	}
+	msgs = append(msgs, s.history...)
+	return msgs
+}
+
+// SessionManager keeps one Session per Telegram user ID.
+type SessionManager struct {
+	mu       sync.Mutex
+	sessions map[int64]*Session
+}
+
+// NewSessionManager returns an empty SessionManager.
+func NewSessionManager() *SessionManager {
+	return &SessionManager{sessions: make(map[int64]*Session)}
+}
+
+// Get returns the session for userID, creating one with defaults if none exists.
+func (m *SessionManager) Get(userID int64) *Session {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.getLocked(userID)
+}
+
+// SetProvider switches userID's active provider, resetting the model
+// pick (the new provider may not know the old one's model) but keeping
+// history and system prompt.
+func (m *SessionManager) SetProvider(userID int64, provider string) {
+	s := m.Get(userID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.provider = provider
+	s.model = ""
+}
+
+// SetModel overrides the model used within the user's current provider.
+func (m *SessionManager) SetModel(userID int64, model string) {
+	s := m.Get(userID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.model = model
+}
+
+// SetSystem overrides the system prompt sent at the start of every
+// completion request for userID.
+func (m *SessionManager) SetSystem(userID int64, prompt string) {
+	s := m.Get(userID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.system = prompt
+}
+
+// Append records a turn in userID's conversation history.
+func (m *SessionManager) Append(userID int64, msg providers.Message) {
+	s := m.Get(userID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.history = append(s.history, msg)
+}
+
+// Reset clears userID's conversation history, keeping the chosen
+// provider, model and system prompt.
+func (m *SessionManager) Reset(userID int64) {
+	s := m.Get(userID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.history = nil
+}
+
+func (m *SessionManager) getLocked(userID int64) *Session {
+	s, ok := m.sessions[userID]
+	if !ok {
+		s = &Session{system: defaultSystemPrompt}
+		m.sessions[userID] = s
+	}
+	return s
+}
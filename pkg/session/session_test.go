@@ -0,0 +1,77 @@
+package session
+
+import (
+	"sync"
+	"testing"
+
+	"ai-gateway-telegram/pkg/providers"
+)
+
+func TestGetCreatesDefaultSession(t *testing.T) {
+	m := NewSessionManager()
+
+	sess := m.Get(1)
+	if sess.Provider() != "" {
+		t.Errorf("new session Provider() = %q, want empty", sess.Provider())
+	}
+	msgs := sess.Messages()
+	if len(msgs) != 1 || msgs[0].Role != providers.RoleSystem {
+		t.Errorf("new session Messages() = %v, want a single default system message", msgs)
+	}
+}
+
+func TestGetReturnsSameSessionForSameUser(t *testing.T) {
+	m := NewSessionManager()
+
+	if m.Get(1) != m.Get(1) {
+		t.Error("Get(1) returned different *Session pointers across calls")
+	}
+}
+
+func TestSetProviderResetsModel(t *testing.T) {
+	m := NewSessionManager()
+
+	m.SetModel(1, "gpt-4o")
+	m.SetProvider(1, "ChatGPT")
+
+	sess := m.Get(1)
+	if sess.Provider() != "ChatGPT" {
+		t.Errorf("Provider() = %q, want %q", sess.Provider(), "ChatGPT")
+	}
+	if sess.Model() != "" {
+		t.Errorf("Model() = %q after SetProvider, want reset to empty", sess.Model())
+	}
+}
+
+func TestAppendAndReset(t *testing.T) {
+	m := NewSessionManager()
+
+	m.Append(1, providers.Message{Role: providers.RoleUser, Content: "hi"})
+	if got := len(m.Get(1).Messages()); got != 2 {
+		t.Fatalf("Messages() length = %d after one Append, want 2 (system + user)", got)
+	}
+
+	m.Reset(1)
+	if got := len(m.Get(1).Messages()); got != 1 {
+		t.Errorf("Messages() length = %d after Reset, want 1 (system only)", got)
+	}
+}
+
+func TestSessionConcurrentAccess(t *testing.T) {
+	m := NewSessionManager()
+	sess := m.Get(1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			m.Append(1, providers.Message{Role: providers.RoleUser, Content: "x"})
+		}()
+		go func() {
+			defer wg.Done()
+			sess.Messages()
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,32 @@
+package transport
+
+import (
+	"context"
+	"time"
+)
+
+// WatchIdle cancels via cancel once no value has been received on
+// activity for longer than timeout, so a systemd socket-activated unit
+// can shut down between bursts of updates instead of idling forever.
+// It returns immediately; the watch runs until ctx is done.
+func WatchIdle(ctx context.Context, cancel context.CancelFunc, activity <-chan struct{}, timeout time.Duration) {
+	go func() {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+				cancel()
+				return
+			case <-activity:
+				if !timer.Stop() {
+					<-timer.C
+				}
+				timer.Reset(timeout)
+			}
+		}
+	}()
+}
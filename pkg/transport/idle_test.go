@@ -0,0 +1,51 @@
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWatchIdleCancelsAfterTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	activity := make(chan struct{})
+
+	WatchIdle(ctx, cancel, activity, 20*time.Millisecond)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(1 * time.Second):
+		t.Fatal("WatchIdle never cancelled after the idle timeout elapsed")
+	}
+}
+
+func TestWatchIdleResetsTimerOnActivity(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	activity := make(chan struct{})
+
+	WatchIdle(ctx, cancel, activity, 50*time.Millisecond)
+
+	deadline := time.Now().Add(150 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		select {
+		case activity <- struct{}{}:
+		case <-ctx.Done():
+			t.Fatal("WatchIdle cancelled despite ongoing activity")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("context already cancelled right after activity stopped")
+	default:
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(1 * time.Second):
+		t.Fatal("WatchIdle never cancelled once activity stopped")
+	}
+}
@@ -0,0 +1,28 @@
+package transport
+
+import (
+	"context"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Polling receives updates via long polling (bot.GetUpdatesChan).
+type Polling struct {
+	bot *tgbotapi.BotAPI
+}
+
+// NewPolling builds a Polling transport for bot.
+func NewPolling(bot *tgbotapi.BotAPI) *Polling {
+	return &Polling{bot: bot}
+}
+
+func (p *Polling) Start(ctx context.Context) (tgbotapi.UpdatesChannel, error) {
+	u := tgbotapi.NewUpdate(0)
+	u.Timeout = 60
+	return p.bot.GetUpdatesChan(u), nil
+}
+
+func (p *Polling) Stop(ctx context.Context) error {
+	p.bot.StopReceivingUpdates()
+	return nil
+}
@@ -0,0 +1,60 @@
+// Package transport abstracts how the bot receives Telegram updates,
+// so main can switch between long polling and webhooks without
+// touching the dispatch logic.
+package transport
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Transport delivers Telegram updates until Stop is called.
+type Transport interface {
+	// Start begins receiving updates and returns the channel they
+	// arrive on. The channel is closed once Stop has drained any
+	// in-flight updates.
+	Start(ctx context.Context) (tgbotapi.UpdatesChannel, error)
+
+	// Stop gracefully shuts the transport down, waiting for in-flight
+	// updates to finish or ctx to expire, whichever comes first.
+	Stop(ctx context.Context) error
+}
+
+// FromEnv builds the Transport selected by the TRANSPORT env var
+// ("polling", the default, or "webhook"), reading its configuration
+// from the corresponding WEBHOOK_* env vars.
+func FromEnv(bot *tgbotapi.BotAPI) (Transport, error) {
+	switch os.Getenv("TRANSPORT") {
+	case "", "polling":
+		return NewPolling(bot), nil
+
+	case "webhook":
+		cfg := WebhookConfig{
+			URL:         os.Getenv("WEBHOOK_URL"),
+			Listen:      os.Getenv("WEBHOOK_LISTEN"),
+			CertFile:    os.Getenv("WEBHOOK_CERT"),
+			SecretToken: os.Getenv("WEBHOOK_SECRET_TOKEN"),
+		}
+		if cfg.Listen == "" {
+			cfg.Listen = ":8443"
+		}
+		return NewWebhook(bot, cfg)
+
+	default:
+		return nil, fmt.Errorf("transport: unknown TRANSPORT %q", os.Getenv("TRANSPORT"))
+	}
+}
+
+// IdleTimeoutFromEnv parses IDLE_TIMEOUT (in minutes) if set. It
+// returns zero if the variable is unset or invalid, meaning "disabled".
+func IdleTimeoutFromEnv() int {
+	minutes, err := strconv.Atoi(os.Getenv("IDLE_TIMEOUT"))
+	if err != nil || minutes <= 0 {
+		return 0
+	}
+	return minutes
+}
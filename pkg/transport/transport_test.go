@@ -0,0 +1,51 @@
+package transport
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestIdleTimeoutFromEnvParsesMinutes(t *testing.T) {
+	t.Setenv("IDLE_TIMEOUT", "15")
+	if got := IdleTimeoutFromEnv(); got != 15 {
+		t.Errorf("IdleTimeoutFromEnv() = %d, want 15", got)
+	}
+}
+
+func TestIdleTimeoutFromEnvDisabledWhenUnsetOrInvalid(t *testing.T) {
+	t.Setenv("IDLE_TIMEOUT", "")
+	if got := IdleTimeoutFromEnv(); got != 0 {
+		t.Errorf("IdleTimeoutFromEnv() with unset var = %d, want 0", got)
+	}
+
+	t.Setenv("IDLE_TIMEOUT", "-5")
+	if got := IdleTimeoutFromEnv(); got != 0 {
+		t.Errorf("IdleTimeoutFromEnv() with negative value = %d, want 0", got)
+	}
+
+	t.Setenv("IDLE_TIMEOUT", "not-a-number")
+	if got := IdleTimeoutFromEnv(); got != 0 {
+		t.Errorf("IdleTimeoutFromEnv() with invalid value = %d, want 0", got)
+	}
+}
+
+func TestFromEnvDefaultsToPolling(t *testing.T) {
+	t.Setenv("TRANSPORT", "")
+
+	tr, err := FromEnv(&tgbotapi.BotAPI{})
+	if err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+	if _, ok := tr.(*Polling); !ok {
+		t.Errorf("FromEnv() with TRANSPORT unset = %T, want *Polling", tr)
+	}
+}
+
+func TestFromEnvRejectsUnknownTransport(t *testing.T) {
+	t.Setenv("TRANSPORT", "carrier-pigeon")
+
+	if _, err := FromEnv(&tgbotapi.BotAPI{}); err == nil {
+		t.Fatal("FromEnv with unknown TRANSPORT = nil error, want non-nil")
+	}
+}
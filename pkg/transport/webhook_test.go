@@ -0,0 +1,104 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func newTestWebhook(cfg WebhookConfig) *Webhook {
+	return &Webhook{cfg: cfg, updates: make(chan tgbotapi.Update), done: make(chan struct{})}
+}
+
+func TestWebhookConfigPathEmbedsSecretToken(t *testing.T) {
+	cfg := WebhookConfig{SecretToken: "shh"}
+	if got, want := cfg.path(), "/telegram/shh"; got != want {
+		t.Errorf("path() = %q, want %q", got, want)
+	}
+}
+
+func TestHandleRejectsMismatchedSecretHeader(t *testing.T) {
+	wh := newTestWebhook(WebhookConfig{SecretToken: "correct"})
+
+	req := httptest.NewRequest(http.MethodPost, "/telegram/correct", bytes.NewReader([]byte("{}")))
+	req.Header.Set("X-Telegram-Bot-Api-Secret-Token", "wrong")
+	rw := httptest.NewRecorder()
+
+	wh.handle(rw, req)
+
+	if rw.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", rw.Code, http.StatusForbidden)
+	}
+}
+
+func TestHandleAcceptsMatchingSecretAndForwardsUpdate(t *testing.T) {
+	wh := newTestWebhook(WebhookConfig{SecretToken: "correct"})
+
+	body, _ := json.Marshal(tgbotapi.Update{UpdateID: 42})
+	req := httptest.NewRequest(http.MethodPost, "/telegram/correct", bytes.NewReader(body))
+	req.Header.Set("X-Telegram-Bot-Api-Secret-Token", "correct")
+	rw := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		wh.handle(rw, req)
+		close(done)
+	}()
+
+	select {
+	case update := <-wh.updates:
+		if update.UpdateID != 42 {
+			t.Errorf("forwarded update ID = %d, want 42", update.UpdateID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handle never forwarded the update onto w.updates")
+	}
+
+	<-done
+	if rw.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rw.Code, http.StatusOK)
+	}
+}
+
+func TestHandleRejectsMalformedBody(t *testing.T) {
+	wh := newTestWebhook(WebhookConfig{})
+
+	req := httptest.NewRequest(http.MethodPost, "/telegram/", bytes.NewReader([]byte("not json")))
+	rw := httptest.NewRecorder()
+
+	wh.handle(rw, req)
+
+	if rw.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rw.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleReturnsServiceUnavailableAfterShutdownSignal(t *testing.T) {
+	wh := newTestWebhook(WebhookConfig{})
+	close(wh.done)
+
+	body, _ := json.Marshal(tgbotapi.Update{})
+	req := httptest.NewRequest(http.MethodPost, "/telegram/", bytes.NewReader(body))
+	rw := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		wh.handle(rw, req)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handle blocked forever trying to send on w.updates after Stop's done was closed")
+	}
+
+	if rw.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rw.Code, http.StatusServiceUnavailable)
+	}
+}
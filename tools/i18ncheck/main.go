@@ -0,0 +1,110 @@
+// Command i18ncheck reports translation keys missing from any *.yaml
+// locale bundle in i18n/, using en.yaml as the canonical key set
+// (.yml and .json bundles are not checked). Run it via
+// `make translation`.
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"ai-gateway-telegram/pkg/i18n"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	dir            = "i18n"
+	canonicalLocal = "en"
+)
+
+func main() {
+	cat, err := i18n.Load(dir, canonicalLocal)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	canonical := keysOf(cat, canonicalLocal)
+
+	locales, err := localesIn(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	missingAny := false
+	for _, locale := range locales {
+		if locale == canonicalLocal {
+			continue
+		}
+
+		have := make(map[string]bool)
+		for _, key := range keysOf(cat, locale) {
+			have[key] = true
+		}
+
+		var missing []string
+		for _, key := range canonical {
+			if !have[key] {
+				missing = append(missing, key)
+			}
+		}
+
+		if len(missing) > 0 {
+			missingAny = true
+			sort.Strings(missing)
+			fmt.Printf("%s: missing %d key(s):\n", locale, len(missing))
+			for _, key := range missing {
+				fmt.Printf("  %s\n", key)
+			}
+		}
+	}
+
+	if missingAny {
+		os.Exit(1)
+	}
+	fmt.Println("all locales fully translated")
+}
+
+func keysOf(cat *i18n.Catalog, locale string) []string {
+	// Read <locale>.yaml directly since Catalog doesn't expose key
+	// listing.
+	raw, err := os.ReadFile(fmt.Sprintf("%s/%s.yaml", dir, locale))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	bundle := make(i18n.Bundle)
+	if err := yaml.Unmarshal(raw, &bundle); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	keys := make([]string, 0, len(bundle))
+	for key := range bundle {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func localesIn(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var locales []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		if len(name) > 5 && name[len(name)-5:] == ".yaml" {
+			locales = append(locales, name[:len(name)-5])
+		}
+	}
+	return locales, nil
+}